@@ -20,18 +20,33 @@ func UploadPage(p AwsPage) {
 	tl, tr := getThumbnailPaths(p)
 	upload(config.AwsBucket(), tl, tr, sess)
 
+	cl, cr := getCardPaths(p)
+	upload(config.AwsBucket(), cl, cr, sess)
+
 	fl, fr := getFilePaths(p)
 	upload(config.AwsBucket(), fl, fr, sess)
 }
 
 func getThumbnailPaths(p AwsPage) (string, string) {
-	localPathToThumbnail := fmt.Sprintf("%sthumb_%s", config.PngDir(), p.GetImageFilename())
+	localPathToThumbnail := fmt.Sprintf("%sthumb_%s", config.UploadDir(), p.GetImageFilename())
 	remotePathToThumbnail := fmt.Sprintf("%s/thumb_%s", config.AwsDir(), p.GetImageFilename())
 	return localPathToThumbnail, remotePathToThumbnail
 }
 
+func UploadCard(p AwsPage) {
+	sess := getAwsSession()
+	cl, cr := getCardPaths(p)
+	upload(config.AwsBucket(), cl, cr, sess)
+}
+
+func getCardPaths(p AwsPage) (string, string) {
+	localPathToCard := fmt.Sprintf("%scard_%s", config.UploadDir(), p.GetImageFilename())
+	remotePathToCard := fmt.Sprintf("%s/card_%s", config.AwsDir(), p.GetImageFilename())
+	return localPathToCard, remotePathToCard
+}
+
 func getFilePaths(p AwsPage) (string, string) {
-	localPathToFile := fmt.Sprintf("%s%s", config.PngDir(), p.GetImageFilename())
+	localPathToFile := fmt.Sprintf("%s%s", config.UploadDir(), p.GetImageFilename())
 	remotePathToFile := fmt.Sprintf("%s/%s", config.AwsDir(), p.GetImageFilename())
 	return localPathToFile, remotePathToFile
 }
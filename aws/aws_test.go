@@ -35,6 +35,23 @@ func TestGetThumbnailPaths(t *testing.T) {
 	}
 }
 
+func TestGetCardPaths(t *testing.T) {
+	config.ReadDirect("/Users/drewing/Sites/gomic.yaml")
+	ap := getAwsPage()
+	expectedLocal := "/Users/drewing/Desktop/devabo_de_uploads/comicstrips/card_DevAbode_0085.png"
+	expectedRemote := "comicstrips/card_DevAbode_0085.png"
+
+	local, remote := getCardPaths(ap)
+
+	if local != expectedLocal {
+		t.Errorf("Expected %s, but got %s", expectedLocal, local)
+	}
+
+	if remote != expectedRemote {
+		t.Errorf("Expected %s, but got %s", expectedRemote, remote)
+	}
+}
+
 func TestGetFilePaths(t *testing.T) {
 	config.ReadDirect("/Users/drewing/Sites/gomic.yaml")
 	ap := getAwsPage()
@@ -0,0 +1,59 @@
+// Package beacon receives the page views the cookie-less beacon of
+// the generated pages sends. It keeps no cookies, ip addresses or
+// user agents, only the time, the path and the referring host.
+package beacon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBody limits the size of a beacon request.
+const maxBody = 1024
+
+type pageView struct {
+	Time     time.Time `json:"time"`
+	Path     string    `json:"path"`
+	Referrer string    `json:"referrer,omitempty"`
+}
+
+// Handler appends each page view as a line of json to the log.
+type Handler struct {
+	mu  sync.Mutex
+	log io.Writer
+	now func() time.Time
+}
+
+func NewHandler(log io.Writer) *Handler {
+	return &Handler{sync.Mutex{}, log, time.Now}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var v pageView
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBody)).Decode(&v); err != nil || !strings.HasPrefix(v.Path, "/") {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	line, err := json.Marshal(pageView{h.now().UTC().Truncate(time.Second), v.Path, v.Referrer})
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, err := h.log.Write(append(line, '\n')); err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,67 @@
+package beacon
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestHandler(log *bytes.Buffer) *Handler {
+	h := NewHandler(log)
+	h.now = func() time.Time {
+		return time.Date(2017, 4, 19, 20, 0, 0, 0, time.UTC)
+	}
+	return h
+}
+
+func TestPageView(t *testing.T) {
+	var log bytes.Buffer
+	h := newTestHandler(&log)
+
+	body := `{"path": "/2017/04/19/85-Test/", "referrer": "example.com", "ip": "1.2.3.4"}`
+	req := httptest.NewRequest("POST", "/beacon", strings.NewReader(body))
+	req.Header.Set("Cookie", "id=1")
+	req.RemoteAddr = "1.2.3.4:5678"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected %d, but got %d", http.StatusNoContent, rec.Code)
+	}
+	if len(rec.Header().Get("Set-Cookie")) > 0 {
+		t.Errorf("Expected no cookie, but got %s", rec.Header().Get("Set-Cookie"))
+	}
+	expected := `{"time":"2017-04-19T20:00:00Z","path":"/2017/04/19/85-Test/","referrer":"example.com"}` + "\n"
+	if log.String() != expected {
+		t.Errorf("Expected %s, but got %s", expected, log.String())
+	}
+}
+
+func TestBadPageViews(t *testing.T) {
+	var log bytes.Buffer
+	h := newTestHandler(&log)
+
+	tests := []struct {
+		method string
+		body   string
+		code   int
+	}{
+		{"GET", "", http.StatusMethodNotAllowed},
+		{"POST", "not json", http.StatusBadRequest},
+		{"POST", `{"path": "https://elsewhere"}`, http.StatusBadRequest},
+		{"POST", `{"path": "/` + strings.Repeat("x", 2*maxBody) + `"}`, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(test.method, "/beacon", strings.NewReader(test.body)))
+		if rec.Code != test.code {
+			t.Errorf("Expected %d for %s %s, but got %d", test.code, test.method, test.body, rec.Code)
+		}
+	}
+	if log.Len() > 0 {
+		t.Errorf("Expected nothing logged, but got %s", log.String())
+	}
+}
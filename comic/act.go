@@ -0,0 +1,76 @@
+package comic
+
+import (
+	"strings"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+type Act struct {
+	name  string
+	pages []*Page
+}
+
+func (a *Act) GetName() string {
+	return a.name
+}
+
+// GetSlug returns the act name as used in file names and paths,
+// e.g. act-iii for Act III.
+func (a *Act) GetSlug() string {
+	return strings.ToLower(createPathTitleFromTitle(a.name))
+}
+
+func (a *Act) GetPages() []*Page {
+	return a.pages
+}
+
+func (a *Act) GetPagesNewestFirst() []*Page {
+	return newestFirst(a.pages)
+}
+
+// FSPath is the path of the act's overview page below the root path.
+func (a *Act) FSPath() string {
+	return "/acts/" + a.GetSlug() + ".html"
+}
+
+func (a *Act) GetPath() string {
+	return config.Servedrootpath() + a.FSPath()
+}
+
+// GetActs groups the pages of the comic by act, keeping the order in
+// which the acts first appear.
+func (c *Comic) GetActs() []*Act {
+	acts := []*Act{}
+	byName := map[string]*Act{}
+	for _, p := range c.pages {
+		a, ok := byName[p.GetAct()]
+		if !ok {
+			a = &Act{p.GetAct(), []*Page{}}
+			byName[p.GetAct()] = a
+			acts = append(acts, a)
+		}
+		a.pages = append(a.pages, p)
+	}
+	return acts
+}
+
+func (c *Comic) GetAct(name string) *Act {
+	for _, a := range c.GetActs() {
+		if a.GetName() == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// GetPageNumber returns the 1-based position of the page within the
+// comic, or 0 if the page isn't part of it.
+func (c *Comic) GetPageNumber(p *Page) int {
+	for i, cp := range c.pages {
+		if cp == p {
+			return i + 1
+		}
+	}
+	return 0
+}
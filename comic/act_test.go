@@ -0,0 +1,65 @@
+package comic
+
+import "testing"
+
+func TestGetActs(t *testing.T) {
+	pages := createPages()
+	pages[8].Act = "Act II"
+	pages[9].Act = "Act II"
+	c := Comic{"", pages}
+
+	acts := c.GetActs()
+	if len(acts) != 2 {
+		t.Fatalf("Expected 2 acts, but got %d", len(acts))
+	}
+	if acts[0].GetName() != "Act I" || len(acts[0].GetPages()) != 8 {
+		t.Errorf("Expected Act I with 8 pages, but got %s with %d", acts[0].GetName(), len(acts[0].GetPages()))
+	}
+	if acts[1].GetName() != "Act II" || len(acts[1].GetPages()) != 2 {
+		t.Errorf("Expected Act II with 2 pages, but got %s with %d", acts[1].GetName(), len(acts[1].GetPages()))
+	}
+}
+
+func TestGetAct(t *testing.T) {
+	c := Comic{"", createPages()}
+	if c.GetAct("Act I") == nil {
+		t.Error("Expected to find Act I")
+	}
+	if c.GetAct("Act IV") != nil {
+		t.Error("Expected not to find Act IV")
+	}
+}
+
+func TestGetPageNumber(t *testing.T) {
+	pages := createPages()
+	c := Comic{"", pages}
+
+	expected := 3
+	actual := c.GetPageNumber(pages[2])
+	if actual != expected {
+		t.Errorf("Expected %d, but got %d", expected, actual)
+	}
+
+	actual = c.GetPageNumber(getPage())
+	if actual != 0 {
+		t.Errorf("Expected 0, but got %d", actual)
+	}
+}
+
+func TestGetSlug(t *testing.T) {
+	a := &Act{"Act III: The  Return", []*Page{}}
+	expected := "act-iii-the-return"
+	actual := a.GetSlug()
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
+
+func TestActFSPath(t *testing.T) {
+	a := &Act{"Act III", []*Page{}}
+	expected := "/acts/act-iii.html"
+	actual := a.FSPath()
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
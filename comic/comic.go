@@ -16,16 +16,18 @@ func (c *Comic) AddPage(p *Page) {
 	c.pages = append(c.pages, p)
 }
 
-func (c *Comic) Get10LastComicPagesNewestFirst() []*Page {
-	// get splice with last 10 pages
-	last10 := c.pages[len(c.pages)-11:]
-
-	// reorder them, so last issued is first in splice
-	for i := len(last10)/2 - 1; i >= 0; i-- {
-		opp := len(last10) - 1 - i
-		last10[i], last10[opp] = last10[opp], last10[i]
+// GetPagesNewestFirst returns the pages of the comic in reverse
+// order, leaving the comic itself untouched.
+func (c *Comic) GetPagesNewestFirst() []*Page {
+	return newestFirst(c.pages)
+}
+
+func newestFirst(pages []*Page) []*Page {
+	reversed := make([]*Page, len(pages))
+	for i, p := range pages {
+		reversed[len(pages)-1-i] = p
 	}
-	return last10
+	return reversed
 }
 
 func (c *Comic) ConnectPages() {
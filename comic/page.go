@@ -20,6 +20,8 @@ type Pages struct {
 type Page struct {
 	Id, PageNumber                                  int
 	Title, Description, Path, ImgUrl, DisqusId, Act string
+	Transcript                                      string
+	Tags                                            []string
 	first, prev, next, last                         *Page
 	meta, navi                                      [][]string
 }
@@ -70,7 +72,7 @@ func getPageDataFromUser(filename string) (string, string, string, string, strin
 
 func getPageFromFilenameAndUserInput(filename string) *Page {
 	act, title, path, disqusId, imgUrl, description := getPageDataFromUser(filename)
-	return &Page{0, 0, title, description, path, imgUrl, disqusId, act, nil, nil, nil, nil, [][]string{}, [][]string{}}
+	return &Page{0, 0, title, description, path, imgUrl, disqusId, act, "", []string{}, nil, nil, nil, nil, [][]string{}, [][]string{}}
 }
 
 func CreateThumbnail(filename string) {
@@ -112,7 +114,7 @@ func NewPage(
 	imgUrl string,
 	disqusId string,
 	act string) *Page {
-	return &Page{0, 0, title, description, path, imgUrl, disqusId, act,
+	return &Page{0, 0, title, description, path, imgUrl, disqusId, act, "", []string{},
 		nil, nil, nil, nil, [][]string{}, [][]string{}}
 }
 
@@ -144,11 +146,25 @@ func (p *Page) GetDescription() string {
 	return "DevAbo.de - a dystopian science fiction webcomic set in the far future"
 }
 
+func (p *Page) GetTranscript() string {
+	return p.Transcript
+}
+
+// GetTags returns the keywords the page is found by in the search.
+func (p *Page) GetTags() []string {
+	return p.Tags
+}
+
 func (p *Page) GetThumnailUrl() string {
 	thumbUrl := fmt.Sprintf("https://s3-us-west-1.amazonaws.com/devabode-us/%s/thumb_%s", config.AwsDir(), p.GetImageFilename())
 	return thumbUrl
 }
 
+func (p *Page) GetCardUrl() string {
+	cardUrl := fmt.Sprintf("https://s3-us-west-1.amazonaws.com/devabode-us/%s/card_%s", config.AwsDir(), p.GetImageFilename())
+	return cardUrl
+}
+
 func (p *Page) GetDisqusIdentifier() string {
 	return p.DisqusId
 }
@@ -161,6 +177,7 @@ func (p *Page) SetRels(first *Page, prev *Page, next *Page, last *Page) {
 }
 
 func (p *Page) fillMeta() {
+	p.meta = [][]string{}
 	if p.first != nil {
 		p.addMeta("start", p.first.Title, p.first.GetPath())
 	}
@@ -186,6 +203,7 @@ func (p *Page) GetMeta() [][]string {
 }
 
 func (p *Page) fillNavi() {
+	p.navi = [][]string{}
 	if p.first != nil {
 		p.addNavi("first", p.first.Title, p.first.GetPath(), "&lt;&lt; first")
 	}
@@ -201,13 +219,18 @@ func (p *Page) fillNavi() {
 }
 
 func (p *Page) GetDateFromFSPath() string {
+	return p.GetPublishDate().Format(time.RFC1123Z)
+}
+
+// GetPublishDate derives the publishing date from the
+// /yyyy/mm/dd/ prefix of the page path.
+func (p *Page) GetPublishDate() time.Time {
 	parts := strings.Split(p.FSPath(), "/")
 	loc, _ := time.LoadLocation("Europe/Berlin")
 	y, _ := strconv.Atoi(parts[1])
 	m, _ := strconv.Atoi(parts[2])
 	d, _ := strconv.Atoi(parts[3])
-	date := time.Date(y, time.Month(m), d, 20, 0, 0, 0, loc)
-	return date.Format(time.RFC1123Z)
+	return time.Date(y, time.Month(m), d, 20, 0, 0, 0, loc)
 }
 
 func (p *Page) GetAct() string {
@@ -222,6 +245,13 @@ func (p *Page) UrlToNext() string {
 	return p.next.GetPath()
 }
 
+func (p *Page) GetNextImgUrl() string {
+	if p.next != nil {
+		return p.next.GetImgUrl()
+	}
+	return ""
+}
+
 func (p *Page) addNavi(rel string, label string, title string, path string) {
 	n := []string{rel, label, title, path}
 	p.navi = append(p.navi, n)
@@ -78,3 +78,35 @@ func getPage() *Page {
 		"20170419 http://DevAbo.de/?p=20170429",
 		"III")
 }
+
+func TestGetNextImgUrl(t *testing.T) {
+	p := getPage()
+	n := NewPage("#86-Test", "", "/2017/04/26/86-Test", "http://localhost/DevAbode_0086.png", "", "III")
+	p.SetRels(nil, nil, n, n)
+
+	expected := "http://localhost/DevAbode_0086.png"
+	actual := p.GetNextImgUrl()
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+
+	actual = n.GetNextImgUrl()
+	if actual != "" {
+		t.Errorf("Expected no next image url, but got %s", actual)
+	}
+}
+
+func TestGetMetaAndNaviAreStable(t *testing.T) {
+	p := getPage()
+	n := NewPage("#86-Test", "", "/2017/04/26/86-Test", "http://localhost/DevAbode_0086.png", "", "III")
+	p.SetRels(nil, nil, n, n)
+
+	p.GetMeta()
+	p.GetNavi()
+	if len(p.GetMeta()) != 2 || len(p.GetNavi()) != 2 {
+		t.Errorf("Expected 2 meta and navi links, but got %d and %d", len(p.GetMeta()), len(p.GetNavi()))
+	}
+	if p.GetMeta()[0][0] != "next" {
+		t.Errorf("Expected next, but got %s", p.GetMeta()[0][0])
+	}
+}
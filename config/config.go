@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 )
@@ -14,15 +15,94 @@ func main() {
 }
 
 type cnf struct {
-	Url                string              `yaml:"url"`
-	AwsBucket          string              `yaml:"aws_bucket"`
-	AwsDir             string              `yaml:"aws_dir"`
-	Rootpath           string              `yaml:"rootpath"`
-	Servedrootpath     string              `yaml:"servedrootpath"`
-	ServedTestrootpath string              `yaml:"servedtestrootpath"`
-	ServedProdrootpath string              `yaml:"servedprodrootpath"`
-	PngDir             string              `yaml:"pngdir"`
-	Pages              []map[string]string `yaml:"pages"`
+	Url                string                 `yaml:"url"`
+	Title              string                 `yaml:"title"`
+	Description        string                 `yaml:"description"`
+	Language           string                 `yaml:"language"`
+	FeedImage          string                 `yaml:"feedimage"`
+	FeedItems          int                    `yaml:"feeditems"`
+	AwsBucket          string                 `yaml:"aws_bucket"`
+	AwsDir             string                 `yaml:"aws_dir"`
+	Rootpath           string                 `yaml:"rootpath"`
+	Servedrootpath     string                 `yaml:"servedrootpath"`
+	ServedTestrootpath string                 `yaml:"servedtestrootpath"`
+	ServedProdrootpath string                 `yaml:"servedprodrootpath"`
+	PngDir             string                 `yaml:"pngdir"`
+	Logo               string                 `yaml:"logo"`
+	Icon               string                 `yaml:"icon"`
+	Watermark          watermarkCnf           `yaml:"watermark"`
+	Copyright          string                 `yaml:"copyright"`
+	Author             string                 `yaml:"author"`
+	ExportDir          string                 `yaml:"exportdir"`
+	Theme              string                 `yaml:"theme"`
+	Content            string                 `yaml:"content"`
+	Optimize           optimizeCnf            `yaml:"optimize"`
+	Comments           map[string]commentsCnf `yaml:"comments"`
+	CommentsArchive    string                 `yaml:"commentsarchive"`
+	Analytics          analyticsCnf           `yaml:"analytics"`
+	PrivacyUrl         string                 `yaml:"privacyurl"`
+	App                appCnf                 `yaml:"app"`
+	Newsletter         newsletterCnf          `yaml:"newsletter"`
+	Ping               pingCnf                `yaml:"ping"`
+	Pages              []map[string]string    `yaml:"pages"`
+}
+
+type watermarkCnf struct {
+	Text     string  `yaml:"text"`
+	Logo     string  `yaml:"logo"`
+	Position string  `yaml:"position"`
+	Opacity  float64 `yaml:"opacity"`
+}
+
+type optimizeCnf struct {
+	Minify      bool `yaml:"minify"`
+	Precompress bool `yaml:"precompress"`
+}
+
+// commentsCnf configures the comments of a stage, the provider is
+// one of disqus, selfhosted or none.
+type commentsCnf struct {
+	Provider  string `yaml:"provider"`
+	Shortname string `yaml:"shortname"`
+	Endpoint  string `yaml:"endpoint"`
+}
+
+// analyticsCnf configures the page view statistics of the prod stage,
+// the provider is one of google, beacon or none.
+type analyticsCnf struct {
+	Provider string `yaml:"provider"`
+	Property string `yaml:"property"`
+	Endpoint string `yaml:"endpoint"`
+}
+
+// appCnf configures the web app manifest, the name and colors shown
+// when the site is installed on a device.
+type appCnf struct {
+	ShortName       string `yaml:"shortname"`
+	ThemeColor      string `yaml:"themecolor"`
+	BackgroundColor string `yaml:"backgroundcolor"`
+}
+
+// newsletterCnf configures the mails announcing new pages, smtp is
+// the host:port of the mail server. Server is the public url of the
+// subscription service, origin the site allowed to call it.
+type newsletterCnf struct {
+	From      string   `yaml:"from"`
+	To        []string `yaml:"to"`
+	Smtp      string   `yaml:"smtp"`
+	User      string   `yaml:"user"`
+	Announced string   `yaml:"announced"`
+	Server    string   `yaml:"server"`
+	Origin    string   `yaml:"origin"`
+}
+
+// pingCnf configures who is told about new pages after they went
+// live: the WebSub hub of the feeds and the IndexNow endpoint, which
+// needs a key.
+type pingCnf struct {
+	WebSubHub   string `yaml:"websubhub"`
+	IndexNow    string `yaml:"indexnow"`
+	IndexNowKey string `yaml:"indexnowkey"`
 }
 
 var conf *cnf
@@ -57,9 +137,18 @@ func Servedrootpath() string {
 	return ""
 }
 
+// Pages are the links of the footer menu besides the content pages,
+// each with a title, an url and a menu order.
 func Pages() []map[string]string {
-	pgs := conf.Pages
-	return pgs
+	if len(conf.Pages) > 0 {
+		return conf.Pages
+	}
+	return []map[string]string{
+		{"title": "Twitter", "url": "http://twitter.com/devabo_de", "menu": "10"},
+		{"title": "RSS", "url": "/feed/rss.xml", "menu": "30"},
+		{"title": "Archive", "url": "/archive.html", "menu": "40"},
+		{"title": "Search", "url": "/search.html", "menu": "45"},
+	}
 }
 
 func Rootpath() string {
@@ -72,6 +161,332 @@ func PngDir() string {
 	return pd
 }
 
+func Logo() string {
+	return conf.Logo
+}
+
+// Icon is the square png the favicons and app icons are rendered
+// from, none are rendered if it's empty.
+func Icon() string {
+	return conf.Icon
+}
+
+func WatermarkText() string {
+	return conf.Watermark.Text
+}
+
+func WatermarkLogo() string {
+	return conf.Watermark.Logo
+}
+
+func WatermarkPosition() string {
+	if len(conf.Watermark.Position) > 0 {
+		return conf.Watermark.Position
+	}
+	return "bottom-right"
+}
+
+// WatermarkOpacity lies in (0,1], greater values are fully opaque.
+func WatermarkOpacity() float64 {
+	if conf.Watermark.Opacity > 1 {
+		return 1
+	}
+	if conf.Watermark.Opacity > 0 {
+		return conf.Watermark.Opacity
+	}
+	return 0.5
+}
+
+func HasWatermark() bool {
+	return len(conf.Watermark.Text) > 0 || len(conf.Watermark.Logo) > 0
+}
+
+func Copyright() string {
+	return conf.Copyright
+}
+
+func Author() string {
+	if len(conf.Author) > 0 {
+		return conf.Author
+	}
+	return "Ingmar Drewing"
+}
+
+func SiteTitle() string {
+	if len(conf.Title) > 0 {
+		return conf.Title
+	}
+	return "DevAbo.de"
+}
+
+func SiteDescription() string {
+	if len(conf.Description) > 0 {
+		return conf.Description
+	}
+	return "A science-fiction webcomic about the lives of software developers in the far, funny and dystopian future"
+}
+
+// SiteUrl is the canonical url of the production site.
+func SiteUrl() string {
+	if len(conf.Url) > 0 {
+		return strings.TrimSuffix(conf.Url, "/")
+	}
+	return "https://devabo.de"
+}
+
+// FeedImage is the image url feed readers show for the site, the
+// favicon written with the icons if it's empty.
+func FeedImage() string {
+	return conf.FeedImage
+}
+
+// FeedItems is the number of pages per feed document, older pages
+// are found on the following pages of the feed.
+func FeedItems() int {
+	if conf.FeedItems > 0 {
+		return conf.FeedItems
+	}
+	return 10
+}
+
+func Language() string {
+	if len(conf.Language) > 0 {
+		return conf.Language
+	}
+	return "en-US"
+}
+
+// ProcessesImages tells whether images get watermarked or tagged
+// with metadata before they are uploaded.
+func ProcessesImages() bool {
+	return HasWatermark() || len(conf.Copyright) > 0 || len(conf.Author) > 0
+}
+
+// UploadDir is the local directory images are uploaded from. The
+// originals in PngDir are kept untouched when images are processed.
+func UploadDir() string {
+	if ProcessesImages() {
+		return conf.PngDir + "publish/"
+	}
+	return conf.PngDir
+}
+
+// Theme is the directory of the theme the site is rendered with, the
+// embedded default theme is used if it's empty.
+func Theme() string {
+	return conf.Theme
+}
+
+// Minify tells whether generated html, css and js are minified.
+func Minify() bool {
+	return conf.Optimize.Minify
+}
+
+// Precompress tells whether .gz and .br siblings are written for
+// hosts serving precompressed files.
+func Precompress() bool {
+	return conf.Optimize.Precompress
+}
+
+// Content is the directory of the markdown files static pages are
+// rendered from, the embedded default content is used if it's empty.
+func Content() string {
+	return conf.Content
+}
+
+// CommentsProvider is the comments provider of the current stage,
+// disqus if none is configured.
+func CommentsProvider() string {
+	if p := conf.Comments[Stage].Provider; len(p) > 0 {
+		return p
+	}
+	return "disqus"
+}
+
+// DisqusShortname identifies the site at Disqus.
+func DisqusShortname() string {
+	if s := conf.Comments[Stage].Shortname; len(s) > 0 {
+		return s
+	}
+	return "devabode"
+}
+
+// CommentsEndpoint is the url of the self-hosted comments server of
+// the current stage.
+func CommentsEndpoint() string {
+	return conf.Comments[Stage].Endpoint
+}
+
+// CommentsArchive is the Disqus XML export whose comments are
+// rendered statically below the pages, none if it's empty.
+func CommentsArchive() string {
+	return conf.CommentsArchive
+}
+
+// AnalyticsProvider counts the page views on prod, google if none is
+// configured.
+func AnalyticsProvider() string {
+	if len(conf.Analytics.Provider) > 0 {
+		return conf.Analytics.Provider
+	}
+	return "google"
+}
+
+// AnalyticsProperty is the Google Analytics property of the site.
+func AnalyticsProperty() string {
+	if len(conf.Analytics.Property) > 0 {
+		return conf.Analytics.Property
+	}
+	return "UA-49679648-1"
+}
+
+// BeaconEndpoint is the url the cookie-less page view beacon is sent
+// to.
+func BeaconEndpoint() string {
+	if len(conf.Analytics.Endpoint) > 0 {
+		return conf.Analytics.Endpoint
+	}
+	return "/beacon"
+}
+
+// PrivacyUrl is the page explaining what the consent banner asks for.
+func PrivacyUrl() string {
+	if len(conf.PrivacyUrl) > 0 {
+		return conf.PrivacyUrl
+	}
+	return "/imprint.html"
+}
+
+// AppShortName is the name of the installed web app, the site title
+// if none is configured.
+func AppShortName() string {
+	if len(conf.App.ShortName) > 0 {
+		return conf.App.ShortName
+	}
+	return SiteTitle()
+}
+
+// ThemeColor colors the browser ui around the site.
+func ThemeColor() string {
+	if len(conf.App.ThemeColor) > 0 {
+		return conf.App.ThemeColor
+	}
+	return "#FF8800"
+}
+
+// BackgroundColor is shown while the installed web app starts.
+func BackgroundColor() string {
+	if len(conf.App.BackgroundColor) > 0 {
+		return conf.App.BackgroundColor
+	}
+	return "#FFFFFF"
+}
+
+// NewsletterFrom is the sender of the newsletter.
+func NewsletterFrom() string {
+	if len(conf.Newsletter.From) > 0 {
+		return conf.Newsletter.From
+	}
+	return "DevAbo.de <newsletter@devabo.de>"
+}
+
+// NewsletterTo are the recipients of the newsletter.
+func NewsletterTo() []string {
+	return conf.Newsletter.To
+}
+
+// SmtpServer is the host:port the newsletter is sent through.
+func SmtpServer() string {
+	return conf.Newsletter.Smtp
+}
+
+func SmtpUser() string {
+	return conf.Newsletter.User
+}
+
+func SmtpPassword() string {
+	return os.Getenv("GOMIC_SMTP_PASS")
+}
+
+// AnnouncedFile keeps the path of the last page announced by the
+// newsletter.
+func AnnouncedFile() string {
+	if len(conf.Newsletter.Announced) > 0 {
+		return conf.Newsletter.Announced
+	}
+	return "announced.txt"
+}
+
+// NewsletterServer is the public url of the newsletter subscription
+// service the sign up form posts to.
+func NewsletterServer() string {
+	if len(conf.Newsletter.Server) > 0 {
+		return strings.TrimSuffix(conf.Newsletter.Server, "/")
+	}
+	return "https://drewing.eu:16443"
+}
+
+// NewsletterApiPath is where the subscription service answers below
+// the newsletter server.
+const NewsletterApiPath = "/0.1/gomic/newsletter/"
+
+// NewsletterSignUpUrl is the url the sign up form of the site puts the
+// address to.
+func NewsletterSignUpUrl() string {
+	return NewsletterServer() + NewsletterApiPath + "add/"
+}
+
+// NewsletterOrigin is the origin allowed to sign up for the newsletter
+// from the browser, the site url if none is configured.
+func NewsletterOrigin() string {
+	if len(conf.Newsletter.Origin) > 0 {
+		return conf.Newsletter.Origin
+	}
+	return SiteUrl()
+}
+
+// WebSubHub is the hub the feeds advertise and which is notified of
+// new pages, none if it's empty.
+func WebSubHub() string {
+	return conf.Ping.WebSubHub
+}
+
+// IndexNowEndpoint is the search engine endpoint notified of changed
+// urls.
+func IndexNowEndpoint() string {
+	if len(conf.Ping.IndexNow) > 0 {
+		return conf.Ping.IndexNow
+	}
+	return "https://api.indexnow.org/indexnow"
+}
+
+// IndexNowKey proves the site is ours to IndexNow, no pings are sent
+// if it's empty.
+func IndexNowKey() string {
+	return conf.Ping.IndexNowKey
+}
+
+func ExportDir() string {
+	if len(conf.ExportDir) > 0 {
+		return conf.ExportDir
+	}
+	return "export"
+}
+
+// Command returns the subcommand given after the flags, e.g.
+// export-print, or an empty string for the default build.
+func Command() string {
+	return flag.Arg(0)
+}
+
+// CommandArgs returns the arguments following the subcommand.
+func CommandArgs() []string {
+	if flag.NArg() > 1 {
+		return flag.Args()[1:]
+	}
+	return []string{}
+}
+
 func GetTumblData() (string, string, string, string) {
 	consumer_key := os.Getenv("GOMIC_TUMBLR_CONSUMER_KEY")
 	consumer_secret := os.Getenv("GOMIC_TUMBLR_CONSUMER_SECRET")
@@ -119,9 +534,15 @@ func newConfig(yamlPath string) {
 	if Stage == "" {
 		fmt.Println(`Usage:
 
-		gomic -stage=<stage>
+		gomic -stage=<stage> [command]
+
+where <stage> is one of dev, prod, test and [command] is one of
 
-where <stage> is one of dev, prod, test`)
+		export [-act <act>] [-out <dir>]
+		export-print [-act <act>] [-page <number>] [-out <dir>]
+		beacon [-addr <address>] [-log <file>]
+		announce [-out <dir>]
+		newsletter-server [-addr <address>] [-cert <file> -key <file>]`)
 		os.Exit(0)
 	}
 
@@ -28,3 +28,59 @@ func TestIsTest(t *testing.T) {
 		t.Errorf("Expected %t, but got %t", expected, actual)
 	}
 }
+
+func TestUploadDir(t *testing.T) {
+	conf = &cnf{PngDir: "/tmp/comicstrips/"}
+	expected := "/tmp/comicstrips/"
+	actual := UploadDir()
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+
+	conf.Copyright = "Ingmar Drewing"
+	expected = "/tmp/comicstrips/publish/"
+	actual = UploadDir()
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
+
+func TestCommentsProvider(t *testing.T) {
+	oldConf, oldStage := conf, Stage
+	defer func() { conf, Stage = oldConf, oldStage }()
+	conf = &cnf{Comments: map[string]commentsCnf{
+		"dev": {Provider: "selfhosted", Endpoint: "http://localhost:8080/comments"},
+	}}
+	Stage = "prod"
+	if CommentsProvider() != "disqus" || DisqusShortname() != "devabode" {
+		t.Errorf("Expected disqus with devabode, but got %s with %s", CommentsProvider(), DisqusShortname())
+	}
+
+	Stage = "dev"
+	expected := "selfhosted http://localhost:8080/comments"
+	actual := CommentsProvider() + " " + CommentsEndpoint()
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
+
+func TestWatermarkOpacity(t *testing.T) {
+	oldConf := conf
+	defer func() { conf = oldConf }()
+	for opacity, expected := range map[float64]float64{0: 0.5, -1: 0.5, 0.3: 0.3, 1: 1, 1.5: 1, 255: 1} {
+		conf = &cnf{Watermark: watermarkCnf{Opacity: opacity}}
+		if actual := WatermarkOpacity(); actual != expected {
+			t.Errorf("Expected %g for %g, but got %g", expected, opacity, actual)
+		}
+	}
+}
+
+func TestNewsletterSignUpUrl(t *testing.T) {
+	oldConf := conf
+	defer func() { conf = oldConf }()
+	conf = &cnf{Newsletter: newsletterCnf{Server: "https://news.example.com/"}}
+	expected := "https://news.example.com/0.1/gomic/newsletter/add/"
+	if actual := NewsletterSignUpUrl(); actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
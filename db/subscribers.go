@@ -0,0 +1,124 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ingmardrewing/gomic/newsletter"
+)
+
+// Subscribers keeps the newsletter subscribers in the subscribers
+// table. Unlike the pages, the addresses come from the web, so the
+// queries only take them as parameters.
+type Subscribers struct{}
+
+func NewSubscribers() *Subscribers {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS subscribers (
+		email VARCHAR(254) NOT NULL PRIMARY KEY,
+		token CHAR(32) NOT NULL UNIQUE,
+		confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+		notified BIGINT NOT NULL DEFAULT 0,
+		created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		panic(err.Error())
+	}
+	addNotifiedColumn()
+	return &Subscribers{}
+}
+
+// addNotifiedColumn adds the time of the last confirmation mail, as
+// unix seconds, to tables created before it was kept.
+func addNotifiedColumn() {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'subscribers' AND COLUMN_NAME = 'notified'`).Scan(&n)
+	if err == nil && n == 0 {
+		_, err = db.Exec("ALTER TABLE subscribers ADD COLUMN notified BIGINT NOT NULL DEFAULT 0")
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *Subscribers) Get(email string) (*newsletter.Subscriber, error) {
+	sub := &newsletter.Subscriber{}
+	var notified int64
+	err := db.QueryRow("SELECT email, token, confirmed, notified FROM subscribers WHERE email = ?", email).Scan(&sub.Email, &sub.Token, &sub.Confirmed, &notified)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub.Notified = fromUnix(notified)
+	return sub, nil
+}
+
+func (s *Subscribers) Add(sub *newsletter.Subscriber) error {
+	_, err := db.Exec("INSERT INTO subscribers (email, token, confirmed, notified) VALUES (?, ?, ?, ?)", sub.Email, sub.Token, sub.Confirmed, toUnix(sub.Notified))
+	return err
+}
+
+func (s *Subscribers) Notify(email string, at time.Time) error {
+	_, err := db.Exec("UPDATE subscribers SET notified = ? WHERE email = ?", toUnix(at), email)
+	return err
+}
+
+// Confirm checks for the token first, as MySQL doesn't count the rows
+// of an already confirmed subscriber as changed.
+func (s *Subscribers) Confirm(token string) (bool, error) {
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM subscribers WHERE token = ?", token).Scan(&n); err != nil || n == 0 {
+		return false, err
+	}
+	_, err := db.Exec("UPDATE subscribers SET confirmed = TRUE WHERE token = ?", token)
+	return err == nil, err
+}
+
+func (s *Subscribers) Remove(token string) (bool, error) {
+	return affected(db.Exec("DELETE FROM subscribers WHERE token = ?", token))
+}
+
+func (s *Subscribers) Confirmed() ([]*newsletter.Subscriber, error) {
+	rows, err := db.Query("SELECT email, token, confirmed, notified FROM subscribers WHERE confirmed = TRUE ORDER BY created")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	subs := []*newsletter.Subscriber{}
+	for rows.Next() {
+		sub := &newsletter.Subscriber{}
+		var notified int64
+		if err := rows.Scan(&sub.Email, &sub.Token, &sub.Confirmed, &notified); err != nil {
+			return nil, err
+		}
+		sub.Notified = fromUnix(notified)
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// toUnix keeps the zero time as 0, which fromUnix turns back.
+func toUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// affected tells whether a statement changed a row.
+func affected(res sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+type Exporter struct {
+	comic  *comic.Comic
+	outDir string
+}
+
+func NewExporter(c *comic.Comic, outDir string) *Exporter {
+	return &Exporter{c, outDir}
+}
+
+// ExportComic packages the whole comic as cbz and epub.
+func (e *Exporter) ExportComic() ([]string, error) {
+	acts := e.comic.GetActs()
+	b, err := e.newBook(config.SiteTitle(), 0, fileSlug(config.SiteTitle()), acts)
+	if err != nil {
+		return nil, err
+	}
+	return e.write(b)
+}
+
+// ExportAct packages a single act as cbz and epub.
+func (e *Exporter) ExportAct(name string) ([]string, error) {
+	for i, a := range e.comic.GetActs() {
+		if a.GetName() == name {
+			title := config.SiteTitle() + " - " + a.GetName()
+			b, err := e.newBook(title, i+1, a.GetSlug(), []*comic.Act{a})
+			if err != nil {
+				return nil, err
+			}
+			return e.write(b)
+		}
+	}
+	return nil, fmt.Errorf("no act named %q", name)
+}
+
+func (e *Exporter) write(b *book) ([]string, error) {
+	if err := os.MkdirAll(e.outDir, 0755); err != nil {
+		return nil, err
+	}
+	cbz := filepath.Join(e.outDir, b.slug+".cbz")
+	if err := writeZip(cbz, b.writeCbz); err != nil {
+		return nil, err
+	}
+	epub := filepath.Join(e.outDir, b.slug+".epub")
+	if err := writeZip(epub, b.writeEpub); err != nil {
+		return nil, err
+	}
+	return []string{cbz, epub}, nil
+}
+
+type book struct {
+	title  string
+	number int
+	slug   string
+	acts   []*bookAct
+	pages  []*bookPage
+}
+
+type bookAct struct {
+	name  string
+	pages []*bookPage
+}
+
+type bookPage struct {
+	*comic.Page
+	number int
+	index  int
+	width  int
+	height int
+	size   int64
+	src    string
+}
+
+func (bp *bookPage) file() string {
+	return fmt.Sprintf("%04d%s", bp.index+1, filepath.Ext(bp.GetImageFilename()))
+}
+
+func (e *Exporter) newBook(title string, number int, slug string, acts []*comic.Act) (*book, error) {
+	b := &book{title, number, slug, []*bookAct{}, []*bookPage{}}
+	for _, a := range acts {
+		ba := &bookAct{a.GetName(), []*bookPage{}}
+		for _, p := range a.GetPages() {
+			src := config.PngDir() + p.GetImageFilename()
+			w, h, err := imageSize(src)
+			if err != nil {
+				return nil, err
+			}
+			fi, err := os.Stat(src)
+			if err != nil {
+				return nil, err
+			}
+			bp := &bookPage{p, e.comic.GetPageNumber(p), len(b.pages), w, h, fi.Size(), src}
+			ba.pages = append(ba.pages, bp)
+			b.pages = append(b.pages, bp)
+		}
+		b.acts = append(b.acts, ba)
+	}
+	if len(b.pages) == 0 {
+		return nil, fmt.Errorf("%s has no pages", title)
+	}
+	return b, nil
+}
+
+// summary lists the pages with their descriptions and transcripts.
+func (b *book) summary() string {
+	lines := []string{config.SiteDescription()}
+	for _, p := range b.pages {
+		line := p.GetTitle()
+		if len(p.Description) > 0 {
+			line += ": " + p.Description
+		}
+		if len(p.GetTranscript()) > 0 {
+			line += "\n" + p.GetTranscript()
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+func writeZip(path string, content func(*zip.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	if err := content(zw); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func addFile(zw *zip.Writer, name string, src string) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func addString(zw *zip.Writer, name string, content string, method uint16) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func language() string {
+	return strings.Split(config.Language(), "-")[0]
+}
+
+func fileSlug(s string) string {
+	r := strings.NewReplacer(".", "-", " ", "-")
+	return strings.ToLower(r.Replace(s))
+}
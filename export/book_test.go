@@ -0,0 +1,136 @@
+package export
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportComic(t *testing.T) {
+	dir := setupPngDir(t)
+	c := testComic()
+	c.GetPages()[0].Transcript = `Bram: "Where am I?" <wakes up>`
+	e := NewExporter(c, filepath.Join(dir, "books"))
+
+	files, err := e.ExportComic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || filepath.Base(files[0]) != "devabo-de.cbz" || filepath.Base(files[1]) != "devabo-de.epub" {
+		t.Fatalf("Expected devabo-de.cbz and devabo-de.epub, but got %v", files)
+	}
+
+	cbz := readZip(t, files[0])
+	for _, n := range []string{"0001.png", "0002.png", "0003.png", "ComicInfo.xml"} {
+		if _, ok := cbz[n]; !ok {
+			t.Errorf("Expected %s in cbz", n)
+		}
+	}
+	ci := cbz["ComicInfo.xml"]
+	for _, s := range []string{
+		"<Title>DevAbo.de</Title>",
+		"<Writer>Ingmar Drewing</Writer>",
+		"<PageCount>3</PageCount>",
+		`<Page Image="0" Type="FrontCover"`,
+		`Bookmark="#2 Negotiation"`,
+		"#1 A Step in the dark: First page",
+		"Bram: &#34;Where am I?&#34; &lt;wakes up&gt;",
+	} {
+		if !strings.Contains(ci, s) {
+			t.Errorf("Expected ComicInfo.xml to contain %s, but got %s", s, ci)
+		}
+	}
+}
+
+func TestExportEpub(t *testing.T) {
+	dir := setupPngDir(t)
+	c := testComic()
+	c.GetPages()[2].Title = `#3 "Weapons" & <choices>`
+	e := NewExporter(c, filepath.Join(dir, "books"))
+
+	files, err := e.ExportComic()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.OpenReader(files[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	first := r.File[0]
+	if first.Name != "mimetype" || first.Method != zip.Store {
+		t.Errorf("Expected stored mimetype as first entry, but got %s", first.Name)
+	}
+
+	epub := readZip(t, files[1])
+	opf := epub["OEBPS/content.opf"]
+	for _, s := range []string{
+		`<meta property="rendition:layout">pre-paginated</meta>`,
+		`<meta property="dcterms:modified">2013-08-31T18:00:00Z</meta>`,
+		`properties="cover-image"`,
+		`<itemref idref="p0003"/>`,
+	} {
+		if !strings.Contains(opf, s) {
+			t.Errorf("Expected content.opf to contain %s, but got %s", s, opf)
+		}
+	}
+
+	nav := epub["OEBPS/nav.xhtml"]
+	for _, s := range []string{
+		`<li><a href="page-0001.xhtml">Act I</a>`,
+		`<li><a href="page-0003.xhtml">Act II</a>`,
+		`<li><a href="page-0003.xhtml">#3 &#34;Weapons&#34; &amp; &lt;choices&gt;</a></li>`,
+	} {
+		if !strings.Contains(nav, s) {
+			t.Errorf("Expected nav.xhtml to contain %s, but got %s", s, nav)
+		}
+	}
+
+	page := epub["OEBPS/page-0001.xhtml"]
+	if !strings.Contains(page, `alt="#1 A Step in the dark: First page"`) {
+		t.Errorf("Expected description as alt text, but got %s", page)
+	}
+}
+
+func TestExportAct(t *testing.T) {
+	dir := setupPngDir(t)
+	e := NewExporter(testComic(), filepath.Join(dir, "books"))
+
+	files, err := e.ExportAct("Act II")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cbz := readZip(t, files[0])
+	if len(cbz) != 2 {
+		t.Errorf("Expected one image and ComicInfo.xml, but got %d files", len(cbz))
+	}
+	if !strings.Contains(cbz["ComicInfo.xml"], "<Number>2</Number>") {
+		t.Errorf("Expected act number 2, but got %s", cbz["ComicInfo.xml"])
+	}
+
+	if _, err := e.ExportAct("Act IV"); err == nil {
+		t.Error("Expected error for unknown act")
+	}
+}
+
+func readZip(t *testing.T, path string) map[string]string {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	return files
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+type comicInfo struct {
+	XMLName   xml.Name        `xml:"ComicInfo"`
+	Xsi       string          `xml:"xmlns:xsi,attr"`
+	Xsd       string          `xml:"xmlns:xsd,attr"`
+	Title     string          `xml:"Title"`
+	Series    string          `xml:"Series"`
+	Number    int             `xml:"Number,omitempty"`
+	Summary   string          `xml:"Summary"`
+	Writer    string          `xml:"Writer"`
+	Penciller string          `xml:"Penciller"`
+	Web       string          `xml:"Web"`
+	PageCount int             `xml:"PageCount"`
+	Language  string          `xml:"LanguageISO"`
+	Manga     string          `xml:"Manga"`
+	Pages     []comicInfoPage `xml:"Pages>Page"`
+}
+
+type comicInfoPage struct {
+	Image       int    `xml:"Image,attr"`
+	Type        string `xml:"Type,attr,omitempty"`
+	ImageSize   int64  `xml:"ImageSize,attr"`
+	ImageWidth  int    `xml:"ImageWidth,attr"`
+	ImageHeight int    `xml:"ImageHeight,attr"`
+	Bookmark    string `xml:"Bookmark,attr,omitempty"`
+}
+
+func (b *book) comicInfo() ([]byte, error) {
+	ci := comicInfo{
+		Xsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		Xsd:       "http://www.w3.org/2001/XMLSchema",
+		Title:     b.title,
+		Series:    config.SiteTitle(),
+		Number:    b.number,
+		Summary:   b.summary(),
+		Writer:    config.Author(),
+		Penciller: config.Author(),
+		Web:       b.pages[0].GetProdUrl(),
+		PageCount: len(b.pages),
+		Language:  language(),
+		Manga:     "No",
+	}
+	for _, p := range b.pages {
+		cp := comicInfoPage{
+			Image:       p.index,
+			ImageSize:   p.size,
+			ImageWidth:  p.width,
+			ImageHeight: p.height,
+			Bookmark:    p.GetTitle(),
+		}
+		if p.index == 0 {
+			cp.Type = "FrontCover"
+		}
+		ci.Pages = append(ci.Pages, cp)
+	}
+	data, err := xml.MarshalIndent(ci, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func (b *book) writeCbz(zw *zip.Writer) error {
+	for _, p := range b.pages {
+		if err := addFile(zw, p.file(), p.src); err != nil {
+			return err
+		}
+	}
+	ci, err := b.comicInfo()
+	if err != nil {
+		return err
+	}
+	return addString(zw, "ComicInfo.xml", string(ci), zip.Deflate)
+}
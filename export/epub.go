@@ -0,0 +1,227 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+var epubTemplates = template.Must(template.New("epub").Funcs(template.FuncMap{
+	"x": escape,
+}).Parse(epubTemplateDefs))
+
+func (b *book) writeEpub(zw *zip.Writer) error {
+	// the mimetype has to be the first entry and must not be compressed
+	if err := addString(zw, "mimetype", "application/epub+zip", zip.Store); err != nil {
+		return err
+	}
+	if err := b.addTemplate(zw, "META-INF/container.xml", "container", b); err != nil {
+		return err
+	}
+	if err := b.addTemplate(zw, "OEBPS/content.opf", "opf", b); err != nil {
+		return err
+	}
+	if err := b.addTemplate(zw, "OEBPS/nav.xhtml", "nav", b); err != nil {
+		return err
+	}
+	if err := addString(zw, "OEBPS/style.css", epubCss, zip.Deflate); err != nil {
+		return err
+	}
+	for _, p := range b.pages {
+		if err := addFile(zw, "OEBPS/images/"+p.file(), p.src); err != nil {
+			return err
+		}
+		if err := b.addTemplate(zw, "OEBPS/"+p.xhtml(), "page", p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *book) addTemplate(zw *zip.Writer, name string, tmpl string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := epubTemplates.ExecuteTemplate(&buf, tmpl, data); err != nil {
+		return err
+	}
+	return addString(zw, name, buf.String(), zip.Deflate)
+}
+
+func (b *book) Title() string {
+	return b.title
+}
+
+func (b *book) Author() string {
+	return config.Author()
+}
+
+func (b *book) Language() string {
+	return language()
+}
+
+func (b *book) Description() string {
+	return config.SiteDescription()
+}
+
+// Identifier derives a stable uuid from the book title, so that
+// reading systems recognize a re-exported book as the same one.
+func (b *book) Identifier() string {
+	h := sha1.Sum([]byte(config.SiteTitle() + "/" + b.title))
+	h[6] = (h[6] & 0x0f) | 0x50
+	h[8] = (h[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+// Modified is the publishing date of the newest page in the book.
+func (b *book) Modified() string {
+	last := b.pages[len(b.pages)-1]
+	return last.GetPublishDate().UTC().Format(time.RFC3339)
+}
+
+func (b *book) Acts() []*bookAct {
+	return b.acts
+}
+
+func (b *book) Pages() []*bookPage {
+	return b.pages
+}
+
+func (b *book) Width() int {
+	return b.pages[0].width
+}
+
+func (b *book) Height() int {
+	return b.pages[0].height
+}
+
+func (ba *bookAct) Name() string {
+	return ba.name
+}
+
+func (ba *bookAct) Pages() []*bookPage {
+	return ba.pages
+}
+
+func (bp *bookPage) xhtml() string {
+	return fmt.Sprintf("page-%04d.xhtml", bp.index+1)
+}
+
+func (bp *bookPage) Xhtml() string {
+	return bp.xhtml()
+}
+
+func (bp *bookPage) File() string {
+	return bp.file()
+}
+
+func (bp *bookPage) Id() string {
+	return fmt.Sprintf("p%04d", bp.index+1)
+}
+
+func (bp *bookPage) IsCover() bool {
+	return bp.index == 0
+}
+
+func (bp *bookPage) Width() int {
+	return bp.width
+}
+
+func (bp *bookPage) Height() int {
+	return bp.height
+}
+
+func (bp *bookPage) Number() int {
+	return bp.number
+}
+
+const epubCss = `html, body { margin: 0; padding: 0; }
+img { display: block; width: 100%; height: 100%; }
+.transcript { position: absolute; left: -10000px; width: 1px; height: 1px; overflow: hidden; }
+`
+
+const epubTemplateDefs = `
+{{define "container"}}<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{end}}
+
+{{define "opf"}}<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid" prefix="rendition: http://www.idpf.org/vocab/rendition/#">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="bookid">{{x .Identifier}}</dc:identifier>
+    <dc:title>{{x .Title}}</dc:title>
+    <dc:creator>{{x .Author}}</dc:creator>
+    <dc:language>{{x .Language}}</dc:language>
+    <dc:description>{{x .Description}}</dc:description>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+    <meta property="rendition:layout">pre-paginated</meta>
+    <meta property="rendition:orientation">portrait</meta>
+    <meta property="rendition:spread">none</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="css" href="style.css" media-type="text/css"/>
+{{- range .Pages}}
+    <item id="{{.Id}}" href="{{.Xhtml}}" media-type="application/xhtml+xml"/>
+    <item id="img-{{.Id}}" href="images/{{.File}}" media-type="image/png"{{if .IsCover}} properties="cover-image"{{end}}/>
+{{- end}}
+  </manifest>
+  <spine>
+{{- range .Pages}}
+    <itemref idref="{{.Id}}"/>
+{{- end}}
+  </spine>
+</package>
+{{end}}
+
+{{define "nav"}}<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="{{x .Language}}" xml:lang="{{x .Language}}">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{x .Title}}</title>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>{{x .Title}}</h1>
+    <ol>
+{{- range .Acts}}
+      <li><a href="{{(index .Pages 0).Xhtml}}">{{x .Name}}</a>
+        <ol>
+{{- range .Pages}}
+          <li><a href="{{.Xhtml}}">{{x .GetTitle}}</a></li>
+{{- end}}
+        </ol>
+      </li>
+{{- end}}
+    </ol>
+  </nav>
+</body>
+</html>
+{{end}}
+
+{{define "page"}}<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+  <meta charset="UTF-8"/>
+  <meta name="viewport" content="width={{.Width}}, height={{.Height}}"/>
+  <title>{{x .GetTitle}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <img src="images/{{.File}}" width="{{.Width}}" height="{{.Height}}" alt="{{x .GetTitle}}{{if .Description}}: {{x .Description}}{{end}}"{{if .GetTranscript}} aria-describedby="transcript"{{end}}/>
+{{- if .GetTranscript}}
+  <div id="transcript" class="transcript">{{x .GetTranscript}}</div>
+{{- end}}
+</body>
+</html>
+{{end}}
+`
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+	"github.com/jung-kurt/gofpdf"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// all measures in mm
+const (
+	trimWidth = 170.0
+	bleed     = 3.0
+	slug      = 22.0
+	markLen   = 8.0
+	markGap   = 2.0
+	qrSize    = 18.0
+)
+
+type Printer struct {
+	comic  *comic.Comic
+	outDir string
+}
+
+func NewPrinter(c *comic.Comic, outDir string) *Printer {
+	return &Printer{c, outDir}
+}
+
+// PrintActs writes one pdf per act.
+func (pr *Printer) PrintActs() ([]string, error) {
+	files := []string{}
+	for _, a := range pr.comic.GetActs() {
+		f, err := pr.PrintAct(a.GetName())
+		if err != nil {
+			return files, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func (pr *Printer) PrintAct(name string) (string, error) {
+	a := pr.comic.GetAct(name)
+	if a == nil {
+		return "", fmt.Errorf("no act named %q", name)
+	}
+	return pr.print(a.GetSlug()+".pdf", a.GetPages())
+}
+
+// PrintPage writes the pdf of the page with the given 1-based number.
+func (pr *Printer) PrintPage(number int) (string, error) {
+	pages := pr.comic.GetPages()
+	if number < 1 || number > len(pages) {
+		return "", fmt.Errorf("no page number %d", number)
+	}
+	return pr.print(fmt.Sprintf("page-%04d.pdf", number), pages[number-1:number])
+}
+
+func (pr *Printer) print(filename string, pages []*comic.Page) (string, error) {
+	if err := os.MkdirAll(pr.outDir, 0755); err != nil {
+		return "", err
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCreator("gomic", false)
+	pdf.SetAutoPageBreak(false, 0)
+	for _, p := range pages {
+		if err := pr.addPage(pdf, p); err != nil {
+			return "", err
+		}
+	}
+	path := filepath.Join(pr.outDir, filename)
+	return path, pdf.OutputFileAndClose(path)
+}
+
+func (pr *Printer) addPage(pdf *gofpdf.Fpdf, p *comic.Page) error {
+	imgPath := config.PngDir() + p.GetImageFilename()
+	w, h, err := imageSize(imgPath)
+	if err != nil {
+		return err
+	}
+	trimHeight := trimWidth * float64(h) / float64(w)
+	pageW := trimWidth + 2*(bleed+slug)
+	pageH := trimHeight + 2*(bleed+slug)
+	pdf.AddPageFormat("P", gofpdf.SizeType{Wd: pageW, Ht: pageH})
+
+	// the art covers the bleed box, overflow is clipped
+	bx, by := slug, slug
+	bw, bh := trimWidth+2*bleed, trimHeight+2*bleed
+	iw, ih := bw, bw*float64(h)/float64(w)
+	if ih < bh {
+		iw, ih = bh*float64(w)/float64(h), bh
+	}
+	pdf.ClipRect(bx, by, bw, bh, false)
+	pdf.ImageOptions(imgPath, bx-(iw-bw)/2, by-(ih-bh)/2, iw, ih, false,
+		gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.ClipEnd()
+
+	drawCropMarks(pdf, slug+bleed, slug+bleed, trimWidth, trimHeight)
+
+	text, qrBox := slugBoxes(pageH)
+	number := pr.comic.GetPageNumber(p)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetXY(text.x, text.y)
+	pdf.CellFormat(text.w, 4, tr(p.GetTitle()), "", 2, "L", false, 0, "")
+	pdf.CellFormat(text.w, 4, tr(fmt.Sprintf("%s, page %d", p.GetAct(), number)), "", 2, "L", false, 0, "")
+	pdf.CellFormat(text.w, 4, p.GetProdUrl(), "", 2, "L", false, 0, "")
+
+	qr, err := qrcode.Encode(p.GetProdUrl(), qrcode.Medium, 512)
+	if err != nil {
+		return err
+	}
+	qrName := fmt.Sprintf("qr-%d", number)
+	pdf.RegisterImageOptionsReader(qrName, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions(qrName, qrBox.x, qrBox.y, qrBox.w, qrBox.h, false,
+		gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	return pdf.Error()
+}
+
+type box struct {
+	x, y, w, h float64
+}
+
+// slugBoxes are the places of the page info and the qr code in the
+// slug beneath the bleed, inset from the vertical crop marks running
+// down along the trim box edges.
+func slugBoxes(pageH float64) (text box, qr box) {
+	x, y := slug+bleed+markGap, pageH-slug+markGap
+	qr = box{slug + bleed + trimWidth - markGap - qrSize, y, qrSize, qrSize}
+	text = box{x, y, qr.x - markGap - x, 12}
+	return text, qr
+}
+
+// drawCropMarks draws marks in line with the trim box edges, starting
+// outside the bleed so they don't show on the trimmed print.
+func drawCropMarks(pdf *gofpdf.Fpdf, x, y, w, h float64) {
+	pdf.SetLineWidth(0.25)
+	pdf.SetDrawColor(0, 0, 0)
+	for _, m := range cropMarks(x, y, w, h) {
+		pdf.Line(m[0], m[1], m[2], m[3])
+	}
+}
+
+// cropMarks are the lines of the crop marks of the trim box as x1,
+// y1, x2, y2.
+func cropMarks(x, y, w, h float64) [][4]float64 {
+	off := bleed + markGap
+	marks := [][4]float64{}
+	for _, cx := range []float64{x, x + w} {
+		marks = append(marks,
+			[4]float64{cx, y - off, cx, y - off - markLen},
+			[4]float64{cx, y + h + off, cx, y + h + off + markLen})
+	}
+	for _, cy := range []float64{y, y + h} {
+		marks = append(marks,
+			[4]float64{x - off, cy, x - off - markLen, cy},
+			[4]float64{x + w + off, cy, x + w + off + markLen, cy})
+	}
+	return marks
+}
+
+func imageSize(path string) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	c, _, err := image.DecodeConfig(f)
+	return c.Width, c.Height, err
+}
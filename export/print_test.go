@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func TestPrintPage(t *testing.T) {
+	dir := setupPngDir(t)
+	c := testComic()
+	pr := NewPrinter(c, filepath.Join(dir, "print"))
+
+	path, err := pr.PrintPage(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Errorf("Expected %s to be a pdf", path)
+	}
+	if filepath.Base(path) != "page-0002.pdf" {
+		t.Errorf("Expected page-0002.pdf, but got %s", filepath.Base(path))
+	}
+}
+
+func TestPrintActs(t *testing.T) {
+	dir := setupPngDir(t)
+	pr := NewPrinter(testComic(), filepath.Join(dir, "print"))
+
+	files, err := pr.PrintActs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 act pdfs, but got %v", files)
+	}
+	if filepath.Base(files[1]) != "act-ii.pdf" {
+		t.Errorf("Expected act-ii.pdf, but got %s", files[1])
+	}
+}
+
+func TestPrintUnknownPage(t *testing.T) {
+	pr := NewPrinter(testComic(), t.TempDir())
+	if _, err := pr.PrintPage(4); err == nil {
+		t.Error("Expected error for unknown page")
+	}
+	if _, err := pr.PrintAct("Act IV"); err == nil {
+		t.Error("Expected error for unknown act")
+	}
+}
+
+func TestSlugClearOfCropMarks(t *testing.T) {
+	trimHeight := 280.0
+	pageH := trimHeight + 2*(bleed+slug)
+	text, qr := slugBoxes(pageH)
+	for _, b := range []box{text, qr} {
+		if b.y+b.h > pageH || b.w <= 0 {
+			t.Errorf("Expected %v to fit into the slug", b)
+		}
+		for _, m := range cropMarks(slug+bleed, slug+bleed, trimWidth, trimHeight) {
+			left, right := math.Min(m[0], m[2]), math.Max(m[0], m[2])
+			top, bottom := math.Min(m[1], m[3]), math.Max(m[1], m[3])
+			if left <= b.x+b.w && right >= b.x && top <= b.y+b.h && bottom >= b.y {
+				t.Errorf("Expected %v to be clear of the crop mark %v", b, m)
+			}
+		}
+	}
+}
+
+func testComic() *comic.Comic {
+	c := comic.NewComic([]*comic.Page{
+		comic.NewPage("#1 A Step in the dark", "First page", "/2013/08/01/a-step-in-the-dark",
+			"https://devabode-us.s3.amazonaws.com/comicstrips/DevAbode_0001.png", "8 http://devabo.de/?p=8", "Act I"),
+		comic.NewPage("#2 Negotiation", "", "/2013/08/31/negotiation",
+			"https://devabode-us.s3.amazonaws.com/comicstrips/DevAbode_0002.png", "35 http://devabo.de/?p=35", "Act I"),
+		comic.NewPage("#3 Weapon of choice", "", "/2013/08/31/weapon-of-choice",
+			"https://devabode-us.s3.amazonaws.com/comicstrips/DevAbode_0003.png", "79 http://devabo.de/?p=79", "Act II"),
+	})
+	c.ConnectPages()
+	return &c
+}
+
+func setupPngDir(t *testing.T) string {
+	dir := t.TempDir()
+	pngDir := dir + "/comicstrips/"
+	os.MkdirAll(pngDir, 0755)
+	for _, n := range []string{"DevAbode_0001.png", "DevAbode_0002.png", "DevAbode_0003.png"} {
+		f, err := os.Create(pngDir + n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		png.Encode(f, image.NewGray(image.Rect(0, 0, 80, 133)))
+		f.Close()
+	}
+	yaml := filepath.Join(dir, "gomic.yaml")
+	ioutil.WriteFile(yaml, []byte("rootpath: "+dir+"/site\npngdir: "+pngDir+"\n"), 0644)
+	config.ReadDirect(yaml)
+	return dir
+}
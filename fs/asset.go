@@ -0,0 +1,88 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// asset is a theme asset published under a fingerprinted filename,
+// e.g. css/style.3f2a9c01d4.css, so it can be cached forever and
+// only changes its url when its content changes.
+type asset struct {
+	name    string
+	content string
+}
+
+func newAsset(name string, content string) *asset {
+	return &asset{name, content}
+}
+
+func (a *asset) hash() string {
+	sum := sha256.Sum256([]byte(a.content))
+	return fmt.Sprintf("%x", sum)[:10]
+}
+
+func (a *asset) dir() string {
+	return path.Dir(a.name)
+}
+
+func (a *asset) base() string {
+	b := path.Base(a.name)
+	return strings.TrimSuffix(b, path.Ext(b))
+}
+
+func (a *asset) filename() string {
+	return a.base() + "." + a.hash() + path.Ext(a.name)
+}
+
+// path is the fingerprinted path relative to the site root.
+func (a *asset) path() string {
+	return a.dir() + "/" + a.filename()
+}
+
+// integrity is the Subresource Integrity value of the asset.
+func (a *asset) integrity() string {
+	sum := sha512.Sum384([]byte(a.content))
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// superseded matches other fingerprinted versions of the asset.
+func (a *asset) superseded(filename string) bool {
+	ext := regexp.QuoteMeta(path.Ext(a.name))
+	re := regexp.MustCompile(`^` + regexp.QuoteMeta(a.base()) + `\.[0-9a-f]{10}` + ext + `$`)
+	return re.MatchString(filename) && filename != a.filename()
+}
+
+// write saves the asset below root and removes the fingerprinted
+// files of its previous versions.
+func (a *asset) write(root string) error {
+	dir := filepath.Join(root, a.dir())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, a.filename()), []byte(a.content), 0644); err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if !f.IsDir() && a.superseded(f.Name()) {
+			log.Println("removing superseded asset", f.Name())
+			if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetPath(t *testing.T) {
+	a := newAsset("css/style.css", "body { margin: 0; }")
+
+	expected := "css/style." + a.hash() + ".css"
+	if a.path() != expected {
+		t.Error(fe(expected, a.path()))
+	}
+	if len(a.hash()) != 10 {
+		t.Errorf("Expected a hash of 10 characters, but got %s", a.hash())
+	}
+}
+
+func TestAssetHashFollowsContent(t *testing.T) {
+	a := newAsset("css/style.css", "body { margin: 0; }")
+	b := newAsset("css/style.css", "body { margin: 0; }")
+	c := newAsset("css/style.css", "body { margin: 1px; }")
+
+	if a.filename() != b.filename() {
+		t.Error(fe(a.filename(), b.filename()))
+	}
+	if a.filename() == c.filename() {
+		t.Errorf("Expected different filenames for different content, but got %s", c.filename())
+	}
+}
+
+func TestAssetIntegrity(t *testing.T) {
+	a := newAsset("js/script.js", "alert('Hello, world.');")
+
+	expected := "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO"
+	if a.integrity() != expected {
+		t.Error(fe(expected, a.integrity()))
+	}
+}
+
+func TestAssetWriteRemovesSuperseded(t *testing.T) {
+	root, err := ioutil.TempDir("", "gomic-assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	old := newAsset("css/style.css", "body { margin: 1px; }")
+	if err := old.write(root); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(root, "css", "custom.css"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(root, "css", "style.css"), []byte(""), 0644)
+
+	a := newAsset("css/style.css", "body { margin: 0; }")
+	if err := a.write(root); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := ioutil.ReadDir(filepath.Join(root, "css"))
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	expected := "custom.css " + a.filename() + " style.css"
+	actual := strings.Join(names, " ")
+	if actual != expected {
+		t.Error(fe(expected, actual))
+	}
+}
+
+func TestPageReferencesFingerprintedAssets(t *testing.T) {
+	txt := renderTestPage()
+	th := newDefaultTheme()
+
+	css := th.stylesheet()
+	expected := `<link rel="stylesheet" href="/` + css.path() + `" integrity="` + integrityAttr(css) + `" type="text/css">`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+
+	js := th.script()
+	expected = `<script src="/` + js.path() + `" integrity="` + integrityAttr(js) + `" type="text/javascript"></script>`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+}
+
+// integrityAttr is the integrity of an asset as html/template writes
+// it into an attribute, with + escaped.
+func integrityAttr(a *asset) string {
+	return strings.Replace(a.integrity(), "+", "&#43;", -1)
+}
+
+func TestThemeBuildsAssetOnce(t *testing.T) {
+	files := themeFiles()
+	files["assets/css/style.css"] = &fstest.MapFile{Data: []byte("body { margin: 0; }")}
+	th := newThemeFromFiles(files)
+	css := th.stylesheet()
+
+	files["assets/css/style.css"] = &fstest.MapFile{Data: []byte("body { margin: 1px; }")}
+	if th.stylesheet() != css {
+		t.Errorf("Expected the stylesheet %s to be built once", css.path())
+	}
+}
+
+// themeFiles are the least files a theme parses, to add assets to.
+func themeFiles() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html":   &fstest.MapFile{Data: []byte(`{{define "base"}}{{end}}`)},
+		"partials/empty.html": &fstest.MapFile{},
+	}
+}
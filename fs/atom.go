@@ -0,0 +1,74 @@
+package fs
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Lang     string      `xml:"xml:lang,attr"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Id       string      `xml:"id"`
+	Links    []xmlLink   `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Author   atomPerson  `xml:"author"`
+	Icon     string      `xml:"icon"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string       `xml:"title"`
+	Links     []xmlLink    `xml:"link"`
+	Id        string       `xml:"id"`
+	Published string       `xml:"published"`
+	Updated   string       `xml:"updated"`
+	Category  atomCategory `xml:"category"`
+	Summary   atomText     `xml:"summary"`
+	Content   atomText     `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (f *feed) atom() string {
+	doc := atomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		Lang:     f.language,
+		Title:    f.title,
+		Subtitle: f.description,
+		Id:       f.id,
+		Links: append(
+			f.xmlLinks("atom.xml", "application/atom+xml"),
+			xmlLink{f.link, "alternate", "text/html"}),
+		Updated: f.updated().Format(time.RFC3339),
+		Author:  atomPerson{f.author},
+		Icon:    f.image,
+	}
+	for _, i := range f.items {
+		published := i.published.Format(time.RFC3339)
+		doc.Entries = append(doc.Entries, atomEntry{
+			Title:     i.title,
+			Links:     []xmlLink{{i.url, "alternate", "text/html"}},
+			Id:        i.url,
+			Published: published,
+			Updated:   published,
+			Category:  atomCategory{i.act},
+			Summary:   atomText{"html", i.description},
+			Content:   atomText{"html", i.content},
+		})
+	}
+	return marshalFeed(doc)
+}
@@ -0,0 +1,83 @@
+package fs
+
+import (
+	"html/template"
+	"log"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// CommentsProvider renders the comments section below a narrative
+// page. All providers identify a page's thread by
+// commentsIdentifier, so threads stay attached to their pages when
+// the provider changes.
+type CommentsProvider interface {
+	Render(t *theme, p *comic.Page) template.HTML
+}
+
+// newCommentsProvider returns the provider configured for the stage.
+func newCommentsProvider() CommentsProvider {
+	switch config.CommentsProvider() {
+	case "disqus":
+		return &disqusComments{config.DisqusShortname()}
+	case "selfhosted":
+		return &selfHostedComments{config.CommentsEndpoint()}
+	case "none":
+		return &noComments{}
+	}
+	log.Fatalf("unknown comments provider %s", config.CommentsProvider())
+	return nil
+}
+
+// commentsIdentifier is the thread id of a page, the id Disqus got
+// when the page was imported from the old blog, or else its path.
+func commentsIdentifier(p *comic.Page) string {
+	if len(p.GetDisqusIdentifier()) > 0 {
+		return p.GetDisqusIdentifier()
+	}
+	return p.GetPath()
+}
+
+type disqusComments struct {
+	shortname string
+}
+
+type disqusData struct {
+	Shortname  string
+	Title      string
+	Url        string
+	Identifier string
+}
+
+func (d *disqusComments) Render(t *theme, p *comic.Page) template.HTML {
+	return t.renderPartial("disqus", disqusData{d.shortname, p.GetTitle(), p.GetPath() + "/", commentsIdentifier(p)})
+}
+
+// selfHostedComments loads and posts the comments of a page from an
+// endpoint serving them as json, e.g. GET endpoint?thread=<id>.
+type selfHostedComments struct {
+	endpoint string
+}
+
+type selfHostedData struct {
+	Endpoint   string
+	Identifier string
+	JsUrl      string
+	JsSri      string
+}
+
+func (s *selfHostedComments) Render(t *theme, p *comic.Page) template.HTML {
+	js := t.commentsScript()
+	return t.renderPartial("selfhosted", selfHostedData{
+		s.endpoint,
+		commentsIdentifier(p),
+		config.Servedrootpath() + "/" + js.path(),
+		js.integrity()})
+}
+
+type noComments struct{}
+
+func (n *noComments) Render(t *theme, p *comic.Page) template.HTML {
+	return ""
+}
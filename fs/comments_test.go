@@ -0,0 +1,70 @@
+package fs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func commentsPage() *comic.Page {
+	return comic.NewPage("#1 A Step in the dark", "", "/2013/08/01/a-step-in-the-dark", "http://localhost/DevAbode_0001.png", "8 http://devabo.de/?p=8", "Act I")
+}
+
+func TestDisqusComments(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	txt := string(newCommentsProvider().Render(newDefaultTheme(), commentsPage()))
+
+	expected := []string{
+		`this.page.identifier = "8 http://devabo.de/?p=8";`,
+		`this.page.url = 'https://DevAbo.de\/2013\/08\/01\/a-step-in-the-dark\/';`,
+		`s.src = 'https://devabode.disqus.com/embed.js';`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+}
+
+func TestSelfHostedCommentsKeepDisqusThreads(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	th := newDefaultTheme()
+	txt := string((&selfHostedComments{"https://comments.devabo.de/api"}).Render(th, commentsPage()))
+
+	js := th.commentsScript()
+	expected := []string{
+		`<section id="comments" data-endpoint="https://comments.devabo.de/api" data-thread="8 http://devabo.de/?p=8">`,
+		`<script src="/` + js.path() + `" integrity="` + integrityAttr(js) + `" type="text/javascript"></script>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+}
+
+func TestCommentsIdentifierFallsBackToPath(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	p := commentsPage()
+	p.DisqusId = ""
+
+	expected := "/2013/08/01/a-step-in-the-dark"
+	if commentsIdentifier(p) != expected {
+		t.Error(fe(expected, commentsIdentifier(p)))
+	}
+}
+
+func TestNoComments(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	h := NewNarrativePageHtml(commentsPage())
+	h.SetComments(&noComments{})
+	txt := h.writePage(newDefaultTheme())
+
+	for _, e := range []string{"disqus", `id="comments"`} {
+		if strings.Contains(txt, e) {
+			t.Errorf("Expected no %s in %s", e, txt)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package fs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressible are the extensions of generated files which get
+// precompressed siblings.
+var compressible = []string{".html", ".css", ".js", ".xml", ".json", ".txt", ".svg"}
+
+// sizeReport collects the size savings of minification and
+// precompression per file for the build report.
+type sizeReport struct {
+	files map[string]*fileSizes
+}
+
+type fileSizes struct {
+	original int
+	minified int
+	gzip     int
+	brotli   int
+}
+
+func newSizeReport() *sizeReport {
+	return &sizeReport{map[string]*fileSizes{}}
+}
+
+func (r *sizeReport) get(path string) *fileSizes {
+	if s, ok := r.files[path]; ok {
+		return s
+	}
+	s := &fileSizes{}
+	r.files[path] = s
+	return s
+}
+
+func (r *sizeReport) minified(path string, original int, minified int) {
+	s := r.get(path)
+	s.original = original
+	s.minified = minified
+}
+
+func (r *sizeReport) compressed(path string, size int, gz int, br int) {
+	s := r.get(path)
+	if s.original == 0 {
+		s.original = size
+		s.minified = size
+	}
+	s.gzip = gz
+	s.brotli = br
+}
+
+// format lists the files relative to root with their sizes, followed
+// by the totals.
+func (r *sizeReport) format(root string) string {
+	paths := []string{}
+	for p := range r.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	lines := []string{fmt.Sprintf("%-50s %10s %10s %10s %10s", "file", "original", "minified", "gzip", "brotli")}
+	total := fileSizes{}
+	for _, p := range paths {
+		s := r.files[p]
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			rel = p
+		}
+		lines = append(lines, s.format(rel))
+		total.original += s.original
+		total.minified += s.minified
+		total.gzip += s.gzip
+		total.brotli += s.brotli
+	}
+	lines = append(lines, total.format("total"))
+	return strings.Join(lines, "\n")
+}
+
+func (s *fileSizes) format(name string) string {
+	return fmt.Sprintf("%-50s %10d %10s %10s %10s",
+		name, s.original,
+		saving(s.original, s.minified),
+		saving(s.original, s.gzip),
+		saving(s.original, s.brotli))
+}
+
+func saving(original int, size int) string {
+	if size == 0 || original == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", 100-size*100/original)
+}
+
+// precompress writes a .gz and a .br sibling of every compressible
+// file below root and removes siblings whose source is gone. The
+// output only depends on the input, gzip headers carry neither name
+// nor modification time.
+func precompress(root string, r *sizeReport) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if ext == ".gz" || ext == ".br" {
+			if _, err := os.Stat(strings.TrimSuffix(path, ext)); os.IsNotExist(err) {
+				return os.Remove(path)
+			}
+			return nil
+		}
+		if !isCompressible(path) {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		gz, err := gzipBytes(data)
+		if err != nil {
+			return err
+		}
+		br, err := brotliBytes(data)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path+".gz", gz, 0644); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path+".br", br, 0644); err != nil {
+			return err
+		}
+		r.compressed(path, len(data), len(gz), len(br))
+		return nil
+	})
+}
+
+func isCompressible(path string) bool {
+	ext := filepath.Ext(path)
+	for _, c := range compressible {
+		if c == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func brotliBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func logReport(r *sizeReport, root string) {
+	if len(r.files) > 0 {
+		log.Println("Output sizes:\n" + r.format(root))
+	}
+}
@@ -0,0 +1,90 @@
+package fs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestPrecompress(t *testing.T) {
+	root, err := ioutil.TempDir("", "gomic-precompress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	html := strings.Repeat("<p>hello world</p>\n", 100)
+	ioutil.WriteFile(filepath.Join(root, "index.html"), []byte(html), 0644)
+	ioutil.WriteFile(filepath.Join(root, "image.png"), []byte("png"), 0644)
+	ioutil.WriteFile(filepath.Join(root, "gone.css.gz"), []byte("stale"), 0644)
+
+	r := newSizeReport()
+	if err := precompress(root, r); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, _ := ioutil.ReadFile(filepath.Join(root, "index.html.gz"))
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unzipped, _ := ioutil.ReadAll(zr)
+	if string(unzipped) != html {
+		t.Error(fe(html, string(unzipped)))
+	}
+
+	br, _ := ioutil.ReadFile(filepath.Join(root, "index.html.br"))
+	unbrotlied, _ := ioutil.ReadAll(brotli.NewReader(bytes.NewReader(br)))
+	if string(unbrotlied) != html {
+		t.Error(fe(html, string(unbrotlied)))
+	}
+
+	for _, name := range []string{"image.png.gz", "gone.css.gz"} {
+		if _, err := os.Stat(filepath.Join(root, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to exist", name)
+		}
+	}
+
+	s := r.files[filepath.Join(root, "index.html")]
+	if s.original != len(html) || s.gzip != len(gz) || s.brotli != len(br) {
+		t.Errorf("Expected sizes %d, %d, %d, but got %v", len(html), len(gz), len(br), s)
+	}
+}
+
+func TestPrecompressIsDeterministic(t *testing.T) {
+	data := []byte(strings.Repeat("body { margin: 0; }\n", 50))
+
+	gz1, _ := gzipBytes(data)
+	gz2, _ := gzipBytes(data)
+	if !bytes.Equal(gz1, gz2) {
+		t.Error("Expected identical gzip output")
+	}
+
+	br1, _ := brotliBytes(data)
+	br2, _ := brotliBytes(data)
+	if !bytes.Equal(br1, br2) {
+		t.Error("Expected identical brotli output")
+	}
+}
+
+func TestSizeReport(t *testing.T) {
+	r := newSizeReport()
+	r.minified("/site/css/style.css", 1000, 800)
+	r.compressed("/site/css/style.css", 800, 250, 200)
+	r.compressed("/site/feed/rss.xml", 500, 100, 90)
+
+	expected := `file                                                 original   minified       gzip     brotli
+css/style.css                                            1000        20%        75%        80%
+feed/rss.xml                                              500         0%        80%        82%
+total                                                    1500        14%        77%        81%`
+	txt := r.format("/site")
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
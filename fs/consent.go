@@ -0,0 +1,66 @@
+package fs
+
+import (
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// consentCategory groups the third-party content a reader may opt in
+// to. The theme wraps such content in <template data-consent="name">,
+// consent.js only activates it after the reader agreed.
+type consentCategory struct {
+	Name        string
+	Title       string
+	Description string
+}
+
+var consentCategories = []consentCategory{
+	{"statistics", "Statistics", "Counts page views with Google Analytics."},
+	{"comments", "Comments", "Loads the comments from Disqus."},
+}
+
+// consentScript is a script of the page head. Scripts of a category
+// are loaded only with consent to it, first-party scripts have none
+// and are always loaded.
+type consentScript struct {
+	Category  string
+	Src       string
+	Integrity string
+}
+
+// headScripts are the scripts of the page head in the order they run.
+// They are all served from the site itself, so pages work without
+// any third-party origin.
+func headScripts(t *theme) []consentScript {
+	js := t.script()
+	return []consentScript{
+		{"", config.Servedrootpath() + "/" + js.path(), js.integrity()},
+	}
+}
+
+type privacyData struct {
+	Categories []consentCategory
+	Url        string
+	JsUrl      string
+	JsSri      string
+}
+
+func newPrivacyData(t *theme) privacyData {
+	js := t.consentScript()
+	return privacyData{consentCategories, siteUrl(config.PrivacyUrl()), config.Servedrootpath() + "/" + js.path(), js.integrity()}
+}
+
+type analyticsData struct {
+	Provider string
+	Property string
+	Endpoint string
+}
+
+// newAnalyticsData describes the page view statistics, which are only
+// collected on prod.
+func newAnalyticsData() analyticsData {
+	provider := config.AnalyticsProvider()
+	if !config.IsProd() || provider == "none" {
+		provider = ""
+	}
+	return analyticsData{provider, config.AnalyticsProperty(), config.BeaconEndpoint()}
+}
@@ -0,0 +1,81 @@
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// renderProdPage renders a page of the prod stage with the given
+// analytics provider, the default one if it's empty.
+func renderProdPage(provider string) string {
+	f, err := ioutil.TempFile("", "gomic-yaml")
+	if err != nil {
+		panic(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("analytics:\n  provider: " + provider + "\n  endpoint: https://stats.devabo.de/beacon\n")
+	f.Close()
+
+	config.ReadDirect(f.Name())
+	config.Stage = "prod"
+	defer func() {
+		config.Stage = ""
+		config.ReadDirect("testdata/gomic.yaml")
+	}()
+	return NewDataHtml("<p>Hello</p>", "/about.html").writePage(newDefaultTheme(), "About")
+}
+
+func TestGoogleAnalyticsWaitsForConsent(t *testing.T) {
+	txt := renderProdPage("")
+
+	expected := []string{
+		"<template data-consent=\"statistics\"><script type=\"text/javascript\">\nvar gaProperty = \"UA-49679648-1\";",
+		"ga('create', gaProperty, 'devabo.de');",
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+	if strings.Contains(txt, "UUA-") {
+		t.Error("Expected the opt-out to use the property without typo")
+	}
+}
+
+func TestBeaconAnalytics(t *testing.T) {
+	txt := renderProdPage("beacon")
+
+	expected := `navigator.sendBeacon("https://stats.devabo.de/beacon", JSON.stringify({path: location.pathname, referrer: referrer}));`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+	for _, e := range []string{"google-analytics", "document.cookie"} {
+		if strings.Contains(txt, e) {
+			t.Errorf("Expected no %s with the beacon", e)
+		}
+	}
+}
+
+func TestNoAnalytics(t *testing.T) {
+	for _, txt := range []string{renderProdPage("none"), renderTestPage()} {
+		for _, e := range []string{"google-analytics", "sendBeacon"} {
+			if strings.Contains(txt, e) {
+				t.Errorf("Expected no %s in %s", e, txt)
+			}
+		}
+	}
+}
+
+func TestDisqusWaitsForConsent(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	txt := string(newCommentsProvider().Render(newDefaultTheme(), commentsPage()))
+
+	template := between(txt, `<template data-consent="comments">`, "</template>")
+	if !strings.Contains(template, "https://devabode.disqus.com/embed.js") {
+		t.Errorf("Expected the Disqus embed to wait for consent, but got %s", txt)
+	}
+}
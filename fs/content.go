@@ -0,0 +1,160 @@
+package fs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	iofs "io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ingmardrewing/gomic/config"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	yaml "gopkg.in/yaml.v2"
+)
+
+//go:embed content
+var defaultContentFiles embed.FS
+
+// contentPage is a static page written in markdown, preceded by a
+// yaml front matter:
+//
+//	---
+//	title: Imprint
+//	menutitle: Imprint / Impressum
+//	path: /imprint.html
+//	menu: 50
+//	locale: de
+//	---
+//
+// Pages with a menu order above zero are linked in the footer menu.
+type contentPage struct {
+	Title     string `yaml:"title"`
+	MenuTitle string `yaml:"menutitle"`
+	Path      string `yaml:"path"`
+	Menu      int    `yaml:"menu"`
+	Locale    string `yaml:"locale"`
+	body      template.HTML
+}
+
+var markdown = goldmark.New(
+	goldmark.WithParserOptions(parser.WithAttribute()),
+	// the content is written by the site's author
+	goldmark.WithRendererOptions(gmhtml.WithUnsafe()))
+
+// readContent reads the pages of the configured content directory or
+// the embedded default content.
+func readContent() []*contentPage {
+	var files iofs.FS
+	if len(config.Content()) > 0 {
+		files = os.DirFS(config.Content())
+	} else {
+		sub, err := iofs.Sub(defaultContentFiles, "content")
+		if err != nil {
+			panic(err)
+		}
+		files = sub
+	}
+	pages, err := readContentPages(files)
+	if err != nil {
+		panic(err)
+	}
+	return pages
+}
+
+func readContentPages(files iofs.FS) ([]*contentPage, error) {
+	names, err := iofs.Glob(files, "*.md")
+	if err != nil {
+		return nil, err
+	}
+	pages := []*contentPage{}
+	for _, name := range names {
+		data, err := iofs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		cp, err := parseContentPage(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if len(cp.Path) == 0 {
+			cp.Path = "/" + strings.TrimSuffix(name, ".md") + ".html"
+		}
+		pages = append(pages, cp)
+	}
+	return pages, nil
+}
+
+func parseContentPage(data []byte) (*contentPage, error) {
+	cp := &contentPage{}
+	src := bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	if bytes.HasPrefix(src, []byte("---\n")) {
+		parts := bytes.SplitN(src[4:], []byte("\n---\n"), 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unterminated front matter")
+		}
+		if err := yaml.Unmarshal(parts[0], cp); err != nil {
+			return nil, err
+		}
+		src = parts[1]
+	}
+	var buf bytes.Buffer
+	if err := markdown.Convert(src, &buf); err != nil {
+		return nil, err
+	}
+	cp.body = template.HTML(buf.String())
+	return cp, nil
+}
+
+func (cp *contentPage) menuTitle() string {
+	if len(cp.MenuTitle) > 0 {
+		return cp.MenuTitle
+	}
+	return cp.Title
+}
+
+// fsPath is where the page is written below the root path.
+func (cp *contentPage) fsPath() string {
+	if strings.HasSuffix(cp.Path, "/") {
+		return cp.Path + "index.html"
+	}
+	return cp.Path
+}
+
+type menuLink struct {
+	Title string
+	Url   string
+	order int
+}
+
+// newMenu merges the content pages with a menu order and the
+// configured links into the footer menu.
+func newMenu(pages []*contentPage) []menuLink {
+	links := []menuLink{}
+	for _, cp := range pages {
+		if cp.Menu > 0 {
+			links = append(links, menuLink{cp.menuTitle(), siteUrl(cp.Path), cp.Menu})
+		}
+	}
+	for _, p := range config.Pages() {
+		order, _ := strconv.Atoi(p["menu"])
+		links = append(links, menuLink{p["title"], siteUrl(p["url"]), order})
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].order < links[j].order
+	})
+	return links
+}
+
+// siteUrl prefixes site relative paths with the served root path.
+func siteUrl(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return config.Servedrootpath() + path
+	}
+	return path
+}
@@ -0,0 +1,109 @@
+package fs
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func TestParseContentPage(t *testing.T) {
+	md := `---
+title: Cast
+menutitle: The Cast
+path: /cast/
+menu: 15
+locale: de
+---
+### Ada {#Ada}
+
+Ada is a *developer*.
+`
+	cp, err := parseContentPage([]byte(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cp.Title != "Cast" || cp.menuTitle() != "The Cast" || cp.Menu != 15 || cp.Locale != "de" {
+		t.Errorf("Expected the front matter values, but got %v", cp)
+	}
+
+	expected := "/cast/index.html"
+	if cp.fsPath() != expected {
+		t.Error(fe(expected, cp.fsPath()))
+	}
+
+	expected = "<h3 id=\"Ada\">Ada</h3>\n<p>Ada is a <em>developer</em>.</p>\n"
+	if string(cp.body) != expected {
+		t.Error(fe(expected, string(cp.body)))
+	}
+}
+
+func TestParseContentPageWithoutFrontMatter(t *testing.T) {
+	files := fstest.MapFS{"faq.md": {Data: []byte("Nothing to see.")}}
+	pages, err := readContentPages(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "/faq.html"
+	if pages[0].Path != expected {
+		t.Error(fe(expected, pages[0].Path))
+	}
+	if pages[0].Menu != 0 {
+		t.Errorf("Expected no menu entry, but got %d", pages[0].Menu)
+	}
+}
+
+func TestParseContentPageUnterminatedFrontMatter(t *testing.T) {
+	_, err := parseContentPage([]byte("---\ntitle: Oops\n"))
+	if err == nil {
+		t.Error("Expected an error for unterminated front matter")
+	}
+}
+
+func TestDefaultContent(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	pages := readContent()
+
+	paths := []string{}
+	for _, cp := range pages {
+		paths = append(paths, cp.Path)
+	}
+	expected := "/about.html /imprint.html"
+	actual := strings.Join(paths, " ")
+	if actual != expected {
+		t.Error(fe(expected, actual))
+	}
+}
+
+func TestMenu(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	pages := []*contentPage{
+		{Title: "Imprint", MenuTitle: "Imprint / Impressum", Path: "/imprint.html", Menu: 50},
+		{Title: "Hidden", Path: "/hidden.html"},
+		{Title: "About", Path: "/about.html", Menu: 20},
+	}
+
+	titles := []string{}
+	for _, l := range newMenu(pages) {
+		titles = append(titles, l.Title+" "+l.Url)
+	}
+	expected := "Twitter http://twitter.com/devabo_de, About /about.html, RSS /feed/rss.xml, Archive /archive.html, Search /search.html, Imprint / Impressum /imprint.html"
+	actual := strings.Join(titles, ", ")
+	if actual != expected {
+		t.Error(fe(expected, actual))
+	}
+}
+
+func TestContentPageLocale(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	cp := &contentPage{Title: "Impressum", Path: "/imprint.html", Locale: "de", body: "<p>Angaben</p>"}
+	txt := NewContentPageHtml(cp).writePage(newDefaultTheme(), cp.Title)
+
+	expected := `<html lang="de">`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+}
@@ -0,0 +1,223 @@
+package fs
+
+import (
+	"encoding/xml"
+	"html"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// The types below read the parts of a Disqus XML export the archived
+// comments are rendered from. Threads and posts reference each other
+// by their dsq:id attributes.
+
+type disqusExport struct {
+	Threads []disqusThread `xml:"thread"`
+	Posts   []disqusPost   `xml:"post"`
+}
+
+type disqusThread struct {
+	DsqId      string `xml:"http://disqus.com/disqus-internals id,attr"`
+	Identifier string `xml:"id"`
+	Link       string `xml:"link"`
+	IsDeleted  bool   `xml:"isDeleted"`
+}
+
+type disqusRef struct {
+	DsqId string `xml:"http://disqus.com/disqus-internals id,attr"`
+}
+
+type disqusPost struct {
+	DsqId     string    `xml:"http://disqus.com/disqus-internals id,attr"`
+	Message   string    `xml:"message"`
+	CreatedAt time.Time `xml:"createdAt"`
+	IsDeleted bool      `xml:"isDeleted"`
+	IsSpam    bool      `xml:"isSpam"`
+	Author    string    `xml:"author>name"`
+	Thread    disqusRef `xml:"thread"`
+	Parent    disqusRef `xml:"parent"`
+}
+
+type archivedComment struct {
+	Id      string
+	Author  string
+	Date    time.Time
+	Message template.HTML
+	Replies []*archivedComment
+}
+
+// commentsArchive holds the threaded comments of the pages, by the
+// thread identifier and by the path of the thread's link.
+type commentsArchive struct {
+	byIdentifier map[string][]*archivedComment
+	byPath       map[string][]*archivedComment
+}
+
+// newCommentsArchive reads the configured archive, unless Disqus is
+// embedded and shows the same comments anyway.
+func newCommentsArchive() *commentsArchive {
+	if len(config.CommentsArchive()) == 0 || config.CommentsProvider() == "disqus" {
+		return nil
+	}
+	return readCommentsArchive(config.CommentsArchive())
+}
+
+func readCommentsArchive(path string) *commentsArchive {
+	f, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	a, err := readDisqusExport(f)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// readDisqusExport builds the comment threads from an export, leaving
+// out deleted threads as well as deleted and spam posts. Replies to a
+// left out post are shown on the top level of their thread.
+func readDisqusExport(r io.Reader) (*commentsArchive, error) {
+	var export disqusExport
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	posts := []disqusPost{}
+	for _, p := range export.Posts {
+		if !p.IsDeleted && !p.IsSpam {
+			posts = append(posts, p)
+		}
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreatedAt.Before(posts[j].CreatedAt)
+	})
+
+	comments := map[string]*archivedComment{}
+	for _, p := range posts {
+		comments[p.DsqId] = &archivedComment{p.DsqId, p.Author, p.CreatedAt, sanitizeComment(p.Message), []*archivedComment{}}
+	}
+	threads := map[string][]*archivedComment{}
+	for _, p := range posts {
+		c := comments[p.DsqId]
+		if parent, ok := comments[p.Parent.DsqId]; ok {
+			parent.Replies = append(parent.Replies, c)
+		} else {
+			threads[p.Thread.DsqId] = append(threads[p.Thread.DsqId], c)
+		}
+	}
+
+	a := &commentsArchive{map[string][]*archivedComment{}, map[string][]*archivedComment{}}
+	for _, t := range export.Threads {
+		cs := threads[t.DsqId]
+		if t.IsDeleted || len(cs) == 0 {
+			continue
+		}
+		if len(t.Identifier) > 0 {
+			a.byIdentifier[t.Identifier] = append(a.byIdentifier[t.Identifier], cs...)
+		}
+		if path := threadPath(t.Link); len(path) > 0 {
+			a.byPath[path] = append(a.byPath[path], cs...)
+		}
+	}
+	return a, nil
+}
+
+// threadPath is the path of a thread's link without trailing slash or
+// index.html, as pages are identified by comic.Page.FSPath.
+func threadPath(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(u.Path, "index.html"), "/")
+}
+
+// forPage returns the comments of a page, found by the identifier its
+// Disqus thread had, or else by its path.
+func (a *commentsArchive) forPage(p *comic.Page) []*archivedComment {
+	if a == nil {
+		return nil
+	}
+	if cs, ok := a.byIdentifier[commentsIdentifier(p)]; ok {
+		return cs
+	}
+	return a.byPath[p.FSPath()]
+}
+
+var commentTag = regexp.MustCompile(`<(/?)([a-zA-Z]+)([^>]*)>`)
+var commentHref = regexp.MustCompile(`href\s*=\s*"([^"]*)"`)
+var commentTags = map[string]bool{"p": true, "br": true, "b": true, "i": true, "strong": true, "em": true, "blockquote": true, "a": true}
+
+// sanitizeComment keeps the few tags Disqus allows in comments,
+// without attributes besides the http(s) href of links, escapes all
+// else and balances the tags.
+func sanitizeComment(message string) template.HTML {
+	out := ""
+	open := []string{}
+	text := func(s string) {
+		out += html.EscapeString(html.UnescapeString(s))
+	}
+	last := 0
+	for _, m := range commentTag.FindAllStringSubmatchIndex(message, -1) {
+		text(message[last:m[0]])
+		last = m[1]
+		closing := m[3] > m[2]
+		name := strings.ToLower(message[m[4]:m[5]])
+		if !commentTags[name] {
+			continue
+		}
+		switch {
+		case name == "br":
+			out += "<br>"
+		case closing:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					for _, n := range reverse(open[i:]) {
+						out += "</" + n + ">"
+					}
+					open = open[:i]
+					break
+				}
+			}
+		case name == "a":
+			href := commentHref.FindStringSubmatch(message[m[6]:m[7]])
+			if href == nil || !isHttpUrl(html.UnescapeString(href[1])) {
+				continue
+			}
+			out += `<a href="` + html.EscapeString(html.UnescapeString(href[1])) + `" rel="nofollow ugc">`
+			open = append(open, name)
+		default:
+			out += "<" + name + ">"
+			open = append(open, name)
+		}
+	}
+	text(message[last:])
+	for _, n := range reverse(open) {
+		out += "</" + n + ">"
+	}
+	return template.HTML(out)
+}
+
+func isHttpUrl(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+func reverse(names []string) []string {
+	r := []string{}
+	for i := len(names) - 1; i >= 0; i-- {
+		r = append(r, names[i])
+	}
+	return r
+}
@@ -0,0 +1,102 @@
+package fs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func archivedPages() []*comic.Page {
+	return []*comic.Page{
+		comic.NewPage("#1 A Step in the dark", "", "/2013/08/01/a-step-in-the-dark", "http://localhost/DevAbode_0001.png", "8 http://devabo.de/?p=8", "Act I"),
+		comic.NewPage("#2 Negotiation", "", "/2013/08/31/negotiation", "http://localhost/DevAbode_0002.png", "", "Act I"),
+		comic.NewPage("#3 Weapon of choice", "", "/2013/08/31/weapon-of-choice", "http://localhost/DevAbode_0003.png", "79 http://devabo.de/?p=79", "Act I"),
+	}
+}
+
+func commentAuthors(cs []*archivedComment) string {
+	authors := []string{}
+	for _, c := range cs {
+		a := c.Author
+		if len(c.Replies) > 0 {
+			a += " (" + commentAuthors(c.Replies) + ")"
+		}
+		authors = append(authors, a)
+	}
+	return strings.Join(authors, ", ")
+}
+
+func TestReadDisqusExport(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	a := readCommentsArchive("testdata/disqus_export.xml")
+	pages := archivedPages()
+
+	expected := []string{"Ann <3 (Ingmar), Carl", "Dora", ""}
+	for i, p := range pages {
+		actual := commentAuthors(a.forPage(p))
+		if actual != expected[i] {
+			t.Error(fe(expected[i], actual))
+		}
+	}
+}
+
+func TestSanitizeComment(t *testing.T) {
+	tests := map[string]string{
+		`<p>Great start! <script>alert(1)</script><b onclick="x()">Bold</b></p>`: `<p>Great start! alert(1)<b>Bold</b></p>`,
+		`<a href="javascript:alert(1)">click</a> &amp; <i>more`:                  `click &amp; <i>more</i>`,
+		`<a href="https://example.com/?a=1&amp;b=2" target="_blank">x</a>`:       `<a href="https://example.com/?a=1&amp;b=2" rel="nofollow ugc">x</a>`,
+		`<p><em>unclosed</p> 1 < 2 <br/>`:                                        `<p><em>unclosed</em></p> 1 &lt; 2 <br>`,
+	}
+	for message, expected := range tests {
+		actual := string(sanitizeComment(message))
+		if actual != expected {
+			t.Error(fe(expected, actual))
+		}
+	}
+}
+
+func TestArchivedCommentsOnPage(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	a := readCommentsArchive("testdata/disqus_export.xml")
+	p := archivedPages()[0]
+	h := NewNarrativePageHtml(p)
+	h.SetArchivedComments(a.forPage(p))
+	txt := h.writePage(newDefaultTheme())
+
+	expected := []string{
+		`<section class="archived-comments">`,
+		`<li id="comment-1001"><p class="comment-meta">Ann &lt;3, <time datetime="2013-08-02T10:00:00Z">August 2, 2013</time></p>`,
+		`<div class="comment-text"><p>Thanks, <a href="https://example.com/robot" rel="nofollow ugc">Ann</a>!</p></div>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+	for _, e := range []string{"pills", "I regret this", "<script>alert"} {
+		if strings.Contains(txt, e) {
+			t.Errorf("Expected no %s in %s", e, txt)
+		}
+	}
+}
+
+func TestCommentsArchiveOnlyWithoutDisqus(t *testing.T) {
+	archive, _ := filepath.Abs("testdata/disqus_export.xml")
+	defer func() {
+		config.Stage = ""
+		config.ReadDirect("testdata/gomic.yaml")
+	}()
+	for provider, expected := range map[string]bool{"disqus": false, "selfhosted": true} {
+		yaml := filepath.Join(t.TempDir(), "gomic.yaml")
+		ioutil.WriteFile(yaml, []byte("commentsarchive: "+archive+"\ncomments:\n  prod:\n    provider: "+provider+"\n"), 0644)
+		config.ReadDirect(yaml)
+		config.Stage = "prod"
+		if actual := newCommentsArchive() != nil; actual != expected {
+			t.Errorf("Expected an archive to be %t with %s, but got %t", expected, provider, actual)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package fs
+
+import (
+	"flag"
+	"html"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+var hostileTitles = []string{
+	`Say "Hello"`,
+	`Tom & Jerry's <b>bold</b> move`,
+	`"><script>alert(1)</script>`,
+	`</script><script>alert(1)</script>`,
+	`'; alert(document.cookie); var x='`,
+	`javascript:alert(1)`,
+	"line\nbreak separator",
+}
+
+func hostilePage(title string) *comic.Page {
+	p := comic.NewPage(title, title, "/2017/04/19/85-Test", "http://localhost/DevAbode_0085.png", "", "III")
+	n := comic.NewPage(title, "", "/2017/04/26/86-Test", "http://localhost/DevAbode_0086.png", "", "III")
+	p.SetRels(nil, nil, n, n)
+	return p
+}
+
+func TestHostileTitlesGolden(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	th := newDefaultTheme()
+
+	txt := ""
+	for _, title := range hostileTitles {
+		h := NewNarrativePageHtml(hostilePage(title))
+		page := h.writePage(th)
+		txt += "=== " + title + "\n"
+		txt += string(h.getMetaTags().Render()) + "\n"
+		txt += between(page, "<title>", "</title>") + "\n"
+		txt += between(page, "<main>", "</main>") + "\n"
+	}
+
+	golden := "testdata/hostile_titles.golden"
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(txt), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txt != string(expected) {
+		t.Error(fe(string(expected), txt))
+	}
+}
+
+func TestHostileTitlesDontInjectMarkup(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	th := newDefaultTheme()
+	benign := NewNarrativePageHtml(hostilePage("Benign")).writePage(th)
+
+	for _, title := range hostileTitles {
+		page := NewNarrativePageHtml(hostilePage(title)).writePage(th)
+		for _, tag := range []string{"<script", "</script", "<b>", "<meta", "<a "} {
+			expected := strings.Count(benign, tag)
+			actual := strings.Count(page, tag)
+			if actual != expected {
+				t.Errorf("Expected %d %s for title %q, but got %d", expected, tag, title, actual)
+			}
+		}
+	}
+}
+
+func TestFeedItemEscapesTitle(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	item := newFeedItem(hostilePage(`<b>bold</b>`))
+
+	expected := "&lt;b&gt;bold&lt;/b&gt;"
+	if item.description != expected {
+		t.Error(fe(expected, item.description))
+	}
+}
+
+func TestCreateRawNode(t *testing.T) {
+	n := createNode("p")
+	n.AppendRaw("<b>trusted</b>").AppendText("<i>not</i>")
+
+	expected := "<p><b>trusted</b>&lt;i&gt;not&lt;/i&gt;</p>"
+	txt := n.Render()
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestEmptyTextNode(t *testing.T) {
+	n := createNode("p")
+	n.AppendText("")
+
+	expected := "<p></p>"
+	txt := n.Render()
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func FuzzAttr(f *testing.F) {
+	for _, title := range hostileTitles {
+		f.Add(title)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		if !utf8.ValidString(value) {
+			t.Skip()
+		}
+		txt := createNode("meta").Attr("content", value).Render()
+		if !strings.HasPrefix(txt, `<meta content="`) || !strings.HasSuffix(txt, `">`) {
+			t.Fatalf("Expected a single meta tag, but got %s", txt)
+		}
+		escaped := strings.TrimSuffix(strings.TrimPrefix(txt, `<meta content="`), `">`)
+		if strings.ContainsAny(escaped, `"'<>`) {
+			t.Fatalf("Expected no markup characters in %s", escaped)
+		}
+		if html.UnescapeString(escaped) != value {
+			t.Fatalf("Expected %s to unescape to %s", escaped, value)
+		}
+	})
+}
+
+func FuzzText(f *testing.F) {
+	for _, title := range hostileTitles {
+		f.Add(title)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		if !utf8.ValidString(value) {
+			t.Skip()
+		}
+		txt := createNode("p").AppendText(value).Render()
+		escaped := strings.TrimSuffix(strings.TrimPrefix(txt, "<p>"), "</p>")
+		if strings.ContainsAny(escaped, "<>") {
+			t.Fatalf("Expected no tags in %s", escaped)
+		}
+		if html.UnescapeString(escaped) != value {
+			t.Fatalf("Expected %s to unescape to %s", escaped, value)
+		}
+	})
+}
+
+func between(txt string, start string, end string) string {
+	i := strings.Index(txt, start)
+	j := strings.Index(txt, end)
+	if i < 0 || j < i {
+		return ""
+	}
+	return txt[i : j+len(end)]
+}
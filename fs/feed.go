@@ -0,0 +1,186 @@
+package fs
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// feed is the format independent model the rss, atom and json feeds
+// are written from.
+type feed struct {
+	id          string
+	title       string
+	description string
+	link        string
+	language    string
+	author      string
+	image       string
+	path        string
+	items       []*feedItem
+	rels        []feedRel
+}
+
+// feedRel links a page of a paged feed to the other pages, as
+// described by RFC 5005, with first, last, previous and next.
+type feedRel struct {
+	rel  string
+	path string
+}
+
+type feedItem struct {
+	title        string
+	url          string
+	published    time.Time
+	act          string
+	description  string
+	content      string
+	thumbnailUrl string
+	imageUrl     string
+	imageName    string
+}
+
+// feedFormat is a format the feed is published in, written to
+// path/name below the root path.
+type feedFormat struct {
+	name     string
+	mimeType string
+	title    string
+	render   func(f *feed) string
+}
+
+var feedFormats = []feedFormat{
+	{"rss.xml", "application/rss+xml", "RSS", (*feed).rss},
+	{"atom.xml", "application/atom+xml", "Atom", (*feed).atom},
+	{"feed.json", "application/feed+json", "JSON Feed", (*feed).jsonFeed},
+}
+
+// newComicFeeds creates the paged main feed covering the whole
+// archive, followed by a paged feed per act.
+func newComicFeeds(c *comic.Comic) []*feed {
+	feeds := newPagedFeed(config.SiteTitle(), "/feed/", config.SiteUrl(), c.GetPagesNewestFirst())
+	for _, a := range c.GetActs() {
+		feeds = append(feeds, newPagedFeed(actFeedTitle(a), actFeedPath(a), a.GetPath(), a.GetPagesNewestFirst())...)
+	}
+	return feeds
+}
+
+func actFeedTitle(a *comic.Act) string {
+	return config.SiteTitle() + " - " + a.GetName()
+}
+
+func actFeedPath(a *comic.Act) string {
+	return "/feed/acts/" + a.GetSlug() + "/"
+}
+
+// newPagedFeed splits the pages, newest first, into feed documents of
+// config.FeedItems() items. The first one is found at path, the
+// following ones at path/page/2/, path/page/3/ and so on. All pages
+// share the id of the first one and link to the html page at link.
+func newPagedFeed(title string, path string, link string, pages []*comic.Page) []*feed {
+	size := config.FeedItems()
+	count := (len(pages) + size - 1) / size
+	if count == 0 {
+		count = 1
+	}
+	feeds := []*feed{}
+	for n := 1; n <= count; n++ {
+		f := &feed{
+			id:          feedUrl(path, "atom.xml"),
+			title:       title,
+			description: config.SiteDescription(),
+			link:        link,
+			language:    config.Language(),
+			author:      config.Author(),
+			image:       feedImage(),
+			path:        feedPagePath(path, n),
+			items:       []*feedItem{},
+			rels:        []feedRel{},
+		}
+		for i := (n - 1) * size; i < n*size && i < len(pages); i++ {
+			f.items = append(f.items, newFeedItem(pages[i]))
+		}
+		if count > 1 {
+			f.rels = append(f.rels,
+				feedRel{"first", feedPagePath(path, 1)},
+				feedRel{"last", feedPagePath(path, count)})
+		}
+		if n > 1 {
+			f.rels = append(f.rels, feedRel{"previous", feedPagePath(path, n-1)})
+		}
+		if n < count {
+			f.rels = append(f.rels, feedRel{"next", feedPagePath(path, n+1)})
+		}
+		feeds = append(feeds, f)
+	}
+	return feeds
+}
+
+func feedPagePath(path string, n int) string {
+	if n == 1 {
+		return path
+	}
+	return fmt.Sprintf("%spage/%d/", path, n)
+}
+
+func newFeedItem(p *comic.Page) *feedItem {
+	return &feedItem{
+		title:        p.GetTitle(),
+		url:          p.GetPath(),
+		published:    p.GetPublishDate(),
+		act:          p.GetAct(),
+		description:  html.EscapeString(p.GetTitle()),
+		content:      createNode("img").Attr("src", p.GetImgUrl()).Render(),
+		thumbnailUrl: p.GetThumnailUrl(),
+		imageUrl:     p.GetImgUrl(),
+		imageName:    p.GetImageFilename(),
+	}
+}
+
+func (f *feed) url(name string) string {
+	return feedUrl(f.path, name)
+}
+
+func feedUrl(path string, name string) string {
+	return config.Servedrootpath() + path + name
+}
+
+// relPath returns the path of the feed page with the given relation,
+// or an empty string.
+func (f *feed) relPath(rel string) string {
+	for _, r := range f.rels {
+		if r.rel == rel {
+			return r.path
+		}
+	}
+	return ""
+}
+
+// updated is the publishing date of the newest item.
+func (f *feed) updated() time.Time {
+	updated := time.Time{}
+	for _, i := range f.items {
+		if i.published.After(updated) {
+			updated = i.published
+		}
+	}
+	return updated
+}
+
+type feedLink struct {
+	Type  string
+	Title string
+	Url   string
+}
+
+// feedLinks returns the autodiscovery links of a feed in all formats.
+func feedLinks(title string, path string) []feedLink {
+	links := []feedLink{}
+	for _, format := range feedFormats {
+		links = append(links, feedLink{format.mimeType, title + " " + format.title, feedUrl(path, format.name)})
+	}
+	return links
+}
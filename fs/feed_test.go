@@ -0,0 +1,254 @@
+package fs
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func testFeed() *feed {
+	config.ReadDirect("testdata/gomic.yaml")
+	return &feed{
+		id:          "/feed/atom.xml",
+		title:       "DevAbo.de",
+		description: "A webcomic",
+		link:        "https://devabo.de",
+		language:    "en-US",
+		author:      "Ingmar Drewing",
+		image:       "https://devabo.de/icons/favicon-32x32.png",
+		path:        "/feed/",
+		items: []*feedItem{
+			newFeedItem(comic.NewPage("#2 Tom & Jerry", "", "/2017/04/19/2-Tom-Jerry", "http://localhost/DevAbode_0002.png", "", "Act I")),
+			newFeedItem(comic.NewPage("#1 <Start>", "", "/2017/04/12/1-Start", "http://localhost/DevAbode_0001.png", "", "Act I")),
+		},
+	}
+}
+
+func TestRss(t *testing.T) {
+	txt := testFeed().rss()
+
+	expected := []string{
+		`<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"`,
+		`<atom:link href="/feed/rss.xml" rel="self" type="application/rss+xml"></atom:link>`,
+		`<title>#2 Tom &amp; Jerry</title>`,
+		`<pubDate>Wed, 19 Apr 2017 20:00:00 +0200</pubDate>`,
+		`<guid isPermaLink="false">/2017/04/19/2-Tom-Jerry/index.html</guid>`,
+		`<description>#1 &amp;lt;Start&amp;gt;</description>`,
+		`<content:encoded>&lt;img src=&#34;http://localhost/DevAbode_0002.png&#34;&gt;</content:encoded>`,
+		`<media:title type="plain">DevAbode_0002.png</media:title>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+
+	var doc struct {
+		Items []struct {
+			Title string `xml:"title"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal([]byte(txt), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Items) != 2 || doc.Items[1].Title != "#1 <Start>" {
+		t.Errorf("Expected 2 items, the second titled #1 <Start>, but got %v", doc.Items)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	txt := testFeed().atom()
+
+	expected := []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en-US">`,
+		`<link href="/feed/atom.xml" rel="self" type="application/atom+xml"></link>`,
+		`<link href="https://devabo.de" rel="alternate" type="text/html"></link>`,
+		`<updated>2017-04-19T20:00:00+02:00</updated>`,
+		`<id>/2017/04/12/1-Start</id>`,
+		`<category term="Act I"></category>`,
+		`<summary type="html">#1 &amp;lt;Start&amp;gt;</summary>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+}
+
+func TestJsonFeed(t *testing.T) {
+	txt := testFeed().jsonFeed()
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(txt), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["version"] != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("Expected JSON Feed 1.1, but got %v", doc["version"])
+	}
+	if doc["feed_url"] != "/feed/feed.json" {
+		t.Errorf("Expected /feed/feed.json, but got %v", doc["feed_url"])
+	}
+	items := doc["items"].([]interface{})
+	first := items[0].(map[string]interface{})
+	if first["title"] != "#2 Tom & Jerry" || first["date_published"] != "2017-04-19T20:00:00+02:00" {
+		t.Errorf("Expected the newest page first, but got %v", first)
+	}
+}
+
+func TestFeedAutodiscovery(t *testing.T) {
+	txt := renderTestPage()
+	expected := `<link rel="alternate" type="application/rss&#43;xml" title="DevAbo.de RSS" href="/feed/rss.xml">
+<link rel="alternate" type="application/atom&#43;xml" title="DevAbo.de Atom" href="/feed/atom.xml">
+<link rel="alternate" type="application/feed&#43;json" title="DevAbo.de JSON Feed" href="/feed/feed.json">`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func feedPages(count int) []*comic.Page {
+	pages := []*comic.Page{}
+	for i := 1; i <= count; i++ {
+		path := fmt.Sprintf("/2017/01/%02d/page-%d", i, i)
+		pages = append(pages, comic.NewPage(fmt.Sprintf("#%d", i), "", path, "", "", "Act I"))
+	}
+	return pages
+}
+
+func TestPagedFeed(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	feeds := newPagedFeed("DevAbo.de", "/feed/", "https://devabo.de", feedPages(25))
+
+	paths := []string{}
+	counts := []string{}
+	for _, f := range feeds {
+		paths = append(paths, f.path)
+		counts = append(counts, strconv.Itoa(len(f.items)))
+	}
+	expected := "/feed/ /feed/page/2/ /feed/page/3/"
+	if strings.Join(paths, " ") != expected {
+		t.Error(fe(expected, strings.Join(paths, " ")))
+	}
+	expected = "10 10 5"
+	if strings.Join(counts, " ") != expected {
+		t.Error(fe(expected, strings.Join(counts, " ")))
+	}
+
+	rels := []string{}
+	for _, r := range feeds[1].rels {
+		rels = append(rels, r.rel+" "+r.path)
+	}
+	expected = "first /feed/, last /feed/page/3/, previous /feed/, next /feed/page/3/"
+	if strings.Join(rels, ", ") != expected {
+		t.Error(fe(expected, strings.Join(rels, ", ")))
+	}
+
+	txt := feeds[1].rss()
+	expected = `<atom:link href="/feed/page/3/rss.xml" rel="next" type="application/rss+xml"></atom:link>`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+
+	txt = feeds[1].atom()
+	expected = `<link href="/feed/page/2/atom.xml" rel="self" type="application/atom+xml"></link>`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+
+	txt = feeds[1].jsonFeed()
+	expected = `"next_url": "/feed/page/3/feed.json"`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestPagedFeedOfSmallComic(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+
+	feeds := newPagedFeed("DevAbo.de", "/feed/", "https://devabo.de", feedPages(3))
+	if len(feeds) != 1 || len(feeds[0].items) != 3 || len(feeds[0].rels) != 0 {
+		t.Errorf("Expected a single feed with 3 items and no paging, but got %d feeds", len(feeds))
+	}
+
+	feeds = newPagedFeed("DevAbo.de", "/feed/", "https://devabo.de", []*comic.Page{})
+	if len(feeds) != 1 || len(feeds[0].items) != 0 {
+		t.Errorf("Expected a single empty feed, but got %d feeds", len(feeds))
+	}
+	if strings.Contains(feeds[0].jsonFeed(), "next_url") {
+		t.Error("Expected no next_url in a single page feed")
+	}
+}
+
+func TestComicFeeds(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	pages := feedPages(12)
+	for _, p := range pages[7:] {
+		p.Act = "Act II"
+	}
+	c := comic.NewComic(pages)
+
+	paths := []string{}
+	for _, f := range newComicFeeds(&c) {
+		paths = append(paths, f.path+" "+f.title)
+	}
+	expected := "/feed/ DevAbo.de, /feed/page/2/ DevAbo.de, /feed/acts/act-i/ DevAbo.de - Act I, /feed/acts/act-ii/ DevAbo.de - Act II"
+	if strings.Join(paths, ", ") != expected {
+		t.Error(fe(expected, strings.Join(paths, ", ")))
+	}
+}
+
+func TestActFeedsHaveOwnIdAndLink(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	pages := feedPages(12)
+	for _, p := range pages[7:] {
+		p.Act = "Act II"
+	}
+	c := comic.NewComic(pages)
+	feeds := newComicFeeds(&c)
+	main, act := feeds[0], feeds[len(feeds)-1]
+
+	var mainDoc, actDoc atomFeed
+	xml.Unmarshal([]byte(main.atom()), &mainDoc)
+	xml.Unmarshal([]byte(act.atom()), &actDoc)
+	if mainDoc.Id == actDoc.Id {
+		t.Errorf("Expected the feeds to have different ids, but both have %s", mainDoc.Id)
+	}
+	if !strings.Contains(feeds[1].atom(), "<id>"+mainDoc.Id+"</id>") {
+		t.Errorf("Expected the second page of the main feed to share id %s", mainDoc.Id)
+	}
+
+	actPage := c.GetActs()[1].GetPath()
+	expected := `<link href="` + actPage + `" rel="alternate" type="text/html"></link>`
+	if !strings.Contains(act.atom(), expected) {
+		t.Error(fe(expected, act.atom()))
+	}
+	var doc jsonFeedDoc
+	json.Unmarshal([]byte(act.jsonFeed()), &doc)
+	if doc.HomePageUrl != actPage {
+		t.Error(fe(actPage, doc.HomePageUrl))
+	}
+}
+
+func TestFeedImageIsWrittenFavicon(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	expected := config.SiteUrl() + "/icons/favicon-32x32.png"
+	if actual := feedImage(); actual != expected {
+		t.Error(fe(expected, actual))
+	}
+}
+
+func TestActPageFeedAutodiscovery(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c := comic.NewComic(feedPages(2))
+	txt := NewActHtml(&c, c.GetActs()[0]).writePage(newDefaultTheme())
+
+	expected := `<link rel="alternate" type="application/rss&#43;xml" title="DevAbo.de - Act I RSS" href="/feed/acts/act-i/rss.xml">`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ingmardrewing/gomic/comic"
 	"github.com/ingmardrewing/gomic/config"
+	"github.com/ingmardrewing/gomic/img"
 	"github.com/nfnt/resize"
 )
 
@@ -25,52 +27,104 @@ func ReadImageFilenames() []string {
 	files, _ := ioutil.ReadDir(path)
 	fileNames := []string{}
 	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
 		fileNames = append(fileNames, f.Name())
 	}
 	return fileNames
 }
 
 type Output struct {
-	comic *comic.Comic
+	comic    *comic.Comic
+	theme    *theme
+	report   *sizeReport
+	content  []*contentPage
+	menu     []menuLink
+	archived *commentsArchive
 }
 
 func NewOutput(comic *comic.Comic) *Output {
-	return &Output{comic}
+	content := readContent()
+	return &Output{comic, newTheme(), newSizeReport(), content, newMenu(content), newCommentsArchive()}
 }
 
 func (o *Output) WriteToFilesystem() {
+	o.writeAssets()
+	o.writeIcons()
 	o.writeNarrativePages()
-	o.writeCss()
-	o.writeJs()
 	o.writeArchive()
-	o.writeRss()
-	o.writeAbout()
-	o.writeImprint()
+	o.writeActPages()
+	o.writeFeeds()
+	o.writeSearch()
+	o.writeWebApp()
+	o.writeContentPages()
+	o.writeSitemap()
+	o.optimize()
+}
+
+// optimize writes precompressed siblings of the generated files if
+// configured and reports the savings.
+func (o *Output) optimize() {
+	if config.Precompress() {
+		if err := precompress(config.Rootpath(), o.report); err != nil {
+			panic(err)
+		}
+	}
+	logReport(o.report, config.Rootpath())
 }
 
-func (o *Output) writeAbout() {
-	ah := NewDataHtml(about, config.Servedrootpath()+"/about.html")
-	o.writeStringToFS(config.Rootpath()+"/about.html", ah.writePage("About"))
+func (o *Output) writeContentPages() {
+	for _, cp := range o.content {
+		h := NewContentPageHtml(cp)
+		h.SetMenu(o.menu)
+		absPath := config.Rootpath() + cp.fsPath()
+		o.prepareFileSystem(filepath.Dir(absPath))
+		o.writeStringToFS(absPath, h.writePage(o.theme, cp.Title))
+	}
 }
 
-func (o *Output) writeImprint() {
-	ah := NewDataHtml(imprint, config.Servedrootpath()+"/imprint.html")
-	o.writeStringToFS(config.Rootpath()+"/imprint.html", ah.writePage("Imprint"))
+// writeFeeds replaces the feed directory, so pages of feeds that
+// got shorter don't linger.
+func (o *Output) writeFeeds() {
+	if err := os.RemoveAll(config.Rootpath() + "/feed"); err != nil {
+		panic(err)
+	}
+	for _, f := range newComicFeeds(o.comic) {
+		o.writeFeed(f)
+	}
 }
 
-func (o *Output) writeRss() {
-	rss := newRss(o.comic)
-	path := config.Rootpath() + "/feed/"
-	filename := "rss.xml"
+func (o *Output) writeFeed(f *feed) {
+	path := config.Rootpath() + f.path
 	o.prepareFileSystem(path)
-	log.Println("Writing rss: ", path+filename)
-	o.writeStringToFS(path+filename, rss.Rss())
+	for _, format := range feedFormats {
+		log.Println("Writing feed: ", path+format.name)
+		o.writeStringToFS(path+format.name, format.render(f))
+	}
+}
+
+// writeSearch writes the search index along with the search page.
+func (o *Output) writeSearch() {
+	if err := newSearchIndex(o.comic).write(config.Rootpath()); err != nil {
+		panic(err)
+	}
+	h := NewSearchHtml()
+	h.SetMenu(o.menu)
+	o.writeStringToFS(config.Rootpath()+"/search.html", h.writePage(o.theme))
+}
+
+// writeWebApp writes the web app manifest and the service worker.
+func (o *Output) writeWebApp() {
+	o.writeStringToFS(config.Rootpath()+"/manifest.webmanifest", newWebManifest().json())
+	o.writeStringToFS(config.Rootpath()+"/sw.js", serviceWorker(o.theme, o.comic))
 }
 
 func (o *Output) writeNarrativePages() {
 	for _, p := range o.comic.GetPages() {
 		o.writePageToFileSystem(p)
 	}
+	o.writeStringToFS(config.Rootpath()+"/pages.json", newPageIndex(o.comic).json())
 }
 
 func (o *Output) writeThumbnailFor(p *comic.Page) string {
@@ -107,7 +161,7 @@ func (o *Output) writeThumbnailFor(p *comic.Page) string {
 }
 
 func (o *Output) getBase64FromPngFile(path string) (string, int, int) {
-	imgFile, err := os.Open(path) // a QR code image
+	imgFile, err := os.Open(path) // a thumbnail image
 
 	if err != nil {
 		fmt.Println(err)
@@ -124,7 +178,7 @@ func (o *Output) getBase64FromPngFile(path string) (string, int, int) {
 	fReader.Read(buf)
 	b := base64.StdEncoding.EncodeToString(buf)
 
-	imgFile2, err := os.Open(path) // a QR code image
+	imgFile2, err := os.Open(path) // a thumbnail image
 
 	if err != nil {
 		fmt.Println(err)
@@ -141,33 +195,81 @@ func (o *Output) getBase64FromPngFile(path string) (string, int, int) {
 }
 
 func (o *Output) writeArchive() {
-	list := []string{}
-	for _, p := range o.comic.GetPages() {
+	ah := NewArchiveHtml()
+	o.addArchiveEntries(ah, o.comic.GetPages())
+	o.writeStringToFS(config.Rootpath()+"/archive.html", ah.writePage(o.theme))
+}
+
+func (o *Output) writeActPages() {
+	o.prepareFileSystem(config.Rootpath() + "/acts")
+	for _, a := range o.comic.GetActs() {
+		ah := NewActHtml(o.comic, a)
+		o.addArchiveEntries(ah, a.GetPages())
+		o.writeStringToFS(config.Rootpath()+a.FSPath(), ah.writePage(o.theme))
+	}
+}
+
+func (o *Output) addArchiveEntries(ah *ArchiveHtml, pages []*comic.Page) {
+	ah.SetMenu(o.menu)
+	for _, p := range pages {
 		path := o.writeThumbnailFor(p)
 		b, w, h := o.getBase64FromPngFile(path)
-		list = append(list, fmt.Sprintf(`<li><a href="%s"><img src="data:image/png;base64,%s" width="%d" height="%d" alt="%s" title="%s"></a></li>`, p.GetPath(), b, w, h, p.GetTitle(), p.GetTitle()))
-
+		ah.AddEntry(p, b, w, h)
 	}
+}
 
-	arc := fmt.Sprintf(`<ul class="archive">%s</ul>`, strings.Join(list, "\n"))
-	ah := NewDataHtml(arc, config.Servedrootpath()+"/archive.html")
-	o.writeStringToFS(config.Rootpath()+"/archive.html", ah.writePage("Archive"))
+// writeSitemap writes the sitemap files and a robots.txt referencing
+// them, sitemaps left over from a bigger site are removed. The
+// IndexNow key is written along with them if there is one.
+func (o *Output) writeSitemap() {
+	files := newSitemap(o.comic, o.content).files()
+	old, _ := filepath.Glob(config.Rootpath() + "/sitemap-*.xml")
+	for _, f := range old {
+		if _, ok := files[filepath.Base(f)]; !ok {
+			os.Remove(f)
+		}
+	}
+	for name, xml := range files {
+		log.Println("Writing sitemap: ", name)
+		o.writeStringToFS(config.Rootpath()+"/"+name, xml)
+	}
+	o.writeStringToFS(config.Rootpath()+"/robots.txt", robotsTxt())
+	if len(config.IndexNowKey()) > 0 {
+		o.writeStringToFS(config.Rootpath()+indexNowKeyPath(), config.IndexNowKey())
+	}
 }
 
-func (o *Output) writeCss() {
-	cg := newCss()
-	p := config.Rootpath() + "/css"
-	o.prepareFileSystem(p)
-	fp := p + "/style.css"
-	o.writeStringToFS(fp, cg.getCss())
+func (o *Output) writeAssets() {
+	for _, a := range o.theme.assets() {
+		log.Println("Writing asset: ", a.path())
+		if err := a.write(config.Rootpath()); err != nil {
+			panic(err)
+		}
+		if config.Minify() {
+			original := len(o.theme.read("assets/" + a.name))
+			o.report.minified(filepath.Join(config.Rootpath(), a.path()), original, len(a.content))
+		}
+	}
+	for _, name := range o.theme.images() {
+		path := filepath.Join(config.Rootpath(), strings.TrimPrefix(name, "assets/"))
+		log.Println("Writing image: ", path)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(o.theme.read(name)), 0644); err != nil {
+			panic(err)
+		}
+	}
 }
 
-func (o *Output) writeJs() {
-	js := newJs()
-	p := config.Rootpath() + "/js"
-	o.prepareFileSystem(p)
-	fp := p + "/script.js"
-	o.writeStringToFS(fp, js.getJs())
+// writeIcons renders the favicons and app icons from the configured
+// source image.
+func (o *Output) writeIcons() {
+	if len(config.Icon()) == 0 {
+		return
+	}
+	log.Println("Writing icons from", config.Icon())
+	if err := img.CreateIcons(config.Icon(), config.Rootpath()+"/icons", config.Rootpath(), config.BackgroundColor()); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func (o *Output) writePageToFileSystem(p *comic.Page) {
@@ -175,7 +277,16 @@ func (o *Output) writePageToFileSystem(p *comic.Page) {
 	o.prepareFileSystem(absPath)
 
 	h := NewNarrativePageHtml(p)
-	html := h.writePage()
+	h.SetMenu(o.menu)
+	h.SetComic(o.comic)
+	h.SetArchivedComments(o.archived.forPage(p))
+	bg, uri, err := img.Placeholder(o.writeThumbnailFor(p))
+	if err != nil {
+		log.Printf("no placeholder for %s: %v\n", p.GetImageFilename(), err)
+	} else {
+		h.SetPlaceholder(bg, uri)
+	}
+	html := h.writePage(o.theme)
 	o.writeStringToFS(absPath+"/index.html", html)
 	if p.IsLast() {
 		o.writeStringToFS(config.Rootpath()+"/index.html", html)
@@ -184,6 +295,11 @@ func (o *Output) writePageToFileSystem(p *comic.Page) {
 
 func (o *Output) writeStringToFS(absPath string, html string) {
 	//log.Println("writing html to filesystem: ", absPath)
+	if config.Minify() {
+		minified := minify(absPath, html)
+		o.report.minified(absPath, len(html), len(minified))
+		html = minified
+	}
 	b := []byte(html)
 	err := ioutil.WriteFile(absPath, b, 0644)
 	if err != nil {
@@ -2,7 +2,15 @@ package fs
 
 import (
 	"fmt"
+	iofs "io/fs"
+	"path"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
 )
 
 func TestCreateTextNode(t *testing.T) {
@@ -74,164 +82,177 @@ func TestCreateSimpleDom(t *testing.T) {
 	}
 }
 
-func TestCreateDomWithDoctype(t *testing.T) {
-	h := newHtmlDoc()
-	h.AddToHead(createNode("title").AppendText("Hello World"))
-	h.AddToBody(createNode("div").AppendText("yo"))
-
-	expected := `<!doctype html>
-<html lang="en"><head><title>Hello World</title></head><body><div>yo</div></body></html>`
+func TestRenderStaticPage(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	dh := NewDataHtml("<p>works!</p>", "/about.html")
+	txt := dh.writePage(newDefaultTheme(), "About")
 
-	txt := h.Render()
-	if txt != expected {
-		t.Error(fe(expected, txt))
+	expected := []string{
+		"<!doctype html>\n<html lang=\"en\">",
+		"<title>DevAbo.de | Graphic Novel | About</title>",
+		"<main>\n<p>works!</p>\n</main>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
 	}
 }
 
 func TestGenerateIconLinks(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.addAndroidIconLinks()
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head><link rel="icon" type="image/png" sizes="192x192" href="/icons/android-icon-192x192.png"></head><body></body></html>`
-	if txt != expected {
-		t.Error(fe(expected, txt))
-	}
-
-	hdw.addFaviconLinks()
-	txt = hdw.Render()
-	expected = `<!doctype html>
-<html lang="en"><head><link rel="icon" type="image/png" sizes="192x192" href="/icons/android-icon-192x192.png"><link rel="icon" type="image/png" sizes="32x32" href="/icons/favicon-32x32.png"><link rel="icon" type="image/png" sizes="96x96" href="/icons/favicon-96x96.png"><link rel="icon" type="image/png" sizes="16x16" href="/icons/favicon-16x16.png"></head><body></body></html>`
-	if txt != expected {
-		t.Error(fe(expected, txt))
-	}
-
-	hdw.addAppleIconLinks()
-	txt = hdw.Render()
-	expected = `<!doctype html>
-<html lang="en"><head><link rel="icon" type="image/png" sizes="192x192" href="/icons/android-icon-192x192.png"><link rel="icon" type="image/png" sizes="32x32" href="/icons/favicon-32x32.png"><link rel="icon" type="image/png" sizes="96x96" href="/icons/favicon-96x96.png"><link rel="icon" type="image/png" sizes="16x16" href="/icons/favicon-16x16.png"><link rel="apple-touch-icon" sizes="57x57" href="/icons/apple-icon-57x57.png"><link rel="apple-touch-icon" sizes="60x60" href="/icons/apple-icon-60x60.png"><link rel="apple-touch-icon" sizes="72x72" href="/icons/apple-icon-72x72.png"><link rel="apple-touch-icon" sizes="76x76" href="/icons/apple-icon-76x76.png"><link rel="apple-touch-icon" sizes="114x114" href="/icons/apple-icon-114x114.png"><link rel="apple-touch-icon" sizes="120x120" href="/icons/apple-icon-120x120.png"><link rel="apple-touch-icon" sizes="144x144" href="/icons/apple-icon-144x144.png"><link rel="apple-touch-icon" sizes="152x152" href="/icons/apple-icon-152x152.png"><link rel="apple-touch-icon" sizes="180x180" href="/icons/apple-icon-180x180.png"></head><body></body></html>`
-	if txt != expected {
+	txt := renderTestPage()
+	expected := `<link rel="icon" type="image/png" sizes="192x192" href="/icons/android-icon-192x192.png">
+<link rel="icon" type="image/png" sizes="32x32" href="/icons/favicon-32x32.png">
+<link rel="icon" type="image/png" sizes="96x96" href="/icons/favicon-96x96.png">
+<link rel="icon" type="image/png" sizes="16x16" href="/icons/favicon-16x16.png">
+<link rel="apple-touch-icon" sizes="57x57" href="/icons/apple-icon-57x57.png">
+<link rel="apple-touch-icon" sizes="60x60" href="/icons/apple-icon-60x60.png">
+<link rel="apple-touch-icon" sizes="72x72" href="/icons/apple-icon-72x72.png">
+<link rel="apple-touch-icon" sizes="76x76" href="/icons/apple-icon-76x76.png">
+<link rel="apple-touch-icon" sizes="114x114" href="/icons/apple-icon-114x114.png">
+<link rel="apple-touch-icon" sizes="120x120" href="/icons/apple-icon-120x120.png">
+<link rel="apple-touch-icon" sizes="144x144" href="/icons/apple-icon-144x144.png">
+<link rel="apple-touch-icon" sizes="152x152" href="/icons/apple-icon-152x152.png">
+<link rel="apple-touch-icon" sizes="180x180" href="/icons/apple-icon-180x180.png">`
+	if !strings.Contains(txt, expected) {
 		t.Error(fe(expected, txt))
 	}
 }
 
-func TestGenerateTitle(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.AddTitle("Hello World")
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head><title>Hello World</title></head><body></body></html>`
-	if txt != expected {
-		t.Error(fe(expected, txt))
+func TestScriptsAndStylesAreServedLocally(t *testing.T) {
+	txt := renderTestPage()
+	for _, m := range regexp.MustCompile(`<(script|link rel="stylesheet") [^>]*(src|href)="[^"]*"`).FindAllString(txt, -1) {
+		if strings.Contains(m, "//") {
+			t.Errorf("Expected no third-party origin, but got %s", m)
+		}
 	}
-}
-
-func TestAddGoogleApiLinkToJQuery(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.addGoogleApiLinkToJQuery()
-
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head><script src="https://ajax.googleapis.com/ajax/libs/jquery/3.2.1/jquery.min.js"></script></head><body></body></html>`
-
-	if txt != expected {
-		t.Error(fe(expected, txt))
+	if strings.Contains(txt, "jquery") {
+		t.Errorf("Expected no jQuery in %s", txt)
 	}
 
+	th := newDefaultTheme()
+	css := th.stylesheet()
+	for _, content := range []string{css.content, minify(css.name, css.content)} {
+		urls := regexp.MustCompile(`url\(\s*['"]?([^'")]*)`).FindAllStringSubmatch(content, -1)
+		if len(urls) == 0 {
+			t.Error("Expected the stylesheet to refer to the header image")
+		}
+		for _, m := range urls {
+			if strings.Contains(m[1], "//") {
+				t.Errorf("Expected no third-party origin in the stylesheet, but got %s", m[1])
+			}
+			if _, err := iofs.Stat(th.files, path.Join("assets", path.Dir(css.name), m[1])); err != nil {
+				t.Errorf("Expected %s to be shipped with the theme, but got %v", m[1], err)
+			}
+		}
+	}
 }
 
 func TestAddCopyrightNotifier(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.AddCopyrightNotifier("2017")
-
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head></head><body><div class="copyright">All content including but not limited to the art, characters, story, website design & graphics are &copy; copyright 2013-2017 Ingmar Drewing unless otherwise stated. All rights reserved. Do not copy, alter or reuse without expressed written permission.</div></body></html>`
-
-	if txt != expected {
+	txt := renderTestPage()
+	expected := fmt.Sprintf(`<div class="copyright">All content including but not limited to the art, characters, story, website design &amp; graphics are &copy; copyright 2013-%d Ingmar Drewing unless otherwise stated. All rights reserved. Do not copy, alter or reuse without expressed written permission.</div>`, time.Now().Year())
+	if !strings.Contains(txt, expected) {
 		t.Error(fe(expected, txt))
 	}
 }
 
-func TestAddCokieLawInfo(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.AddCookieLawInfo()
-
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head></head><body><div id="cookie-law-info-bar">This website uses cookies to improve your experience. We'll assume you're ok with this, but you can opt-out if you wish.<a href="#" id="cookie_action_close_header" class="medium cli-plugin-button cli-plugin-main-button">Accept</a> <a href="http://www.drewing.de/blog/impressum-imprint/" id="CONSTANT_OPEN_URL" target="_blank" class="cli-plugin-main-link">Read More</a></div></body></html>`
-
-	if txt != expected {
-		t.Error(fe(expected, txt))
+func TestAddConsentBanner(t *testing.T) {
+	txt := renderTestPage()
+	js := newDefaultTheme().consentScript()
+	expected := []string{
+		`<div id="consent" class="consent" role="dialog" aria-label="Privacy settings" hidden>`,
+		`<a href="/imprint.html">Read more</a>`,
+		`<label data-category="statistics"><input type="checkbox" name="statistics"> <b>Statistics</b>`,
+		`<label data-category="comments"><input type="checkbox" name="comments"> <b>Comments</b>`,
+		`<script src="/` + js.path() + `" integrity="` + integrityAttr(js) + `" type="text/javascript"></script>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+	if strings.Contains(txt, "drewing.de/blog") {
+		t.Errorf("Expected no link to the old blog in %s", txt)
 	}
 }
 
 func TestAddFooterNavi(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.AddFooterNavi(`<ul><li><a href="test.html">test</a></li></ul>`)
-
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head></head><body><footer><nav><ul><li><a href="test.html">test</a></li></ul></nav></footer></body></html>`
-
-	if txt != expected {
+	txt := renderTestPage()
+	expected := `<footer><nav>
+	<a href="http://twitter.com/devabo_de">Twitter</a>
+	<a href="/about.html">About</a>
+	<a href="/feed/rss.xml">RSS</a>
+	<a href="/archive.html">Archive</a>
+	<a href="/search.html">Search</a>
+	<a href="/imprint.html">Imprint / Impressum</a>
+	<a href="#consent" data-consent-action="open">Privacy settings</a>
+</nav></footer>
+<div class="nl_container nl_container_hidden" data-url="https://drewing.eu:16443/0.1/gomic/newsletter/add/"></div>`
+	if !strings.Contains(txt, expected) {
 		t.Error(fe(expected, txt))
 	}
 }
 
-func TestGoogleMetaData(t *testing.T) {
-	google_data := []string{
-		"name", "The Name or Title Here",
-		"description", "This is the page description",
-		"image", "http,//www.example.com/image.jpg",
+func TestStandardMeta(t *testing.T) {
+	txt := renderTestPage()
+	expected := `<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<meta name="robots" content="index,follow">
+<meta name="author" content="Ingmar Drewing">
+<meta name="publisher" content="Ingmar Drewing">
+<meta name="keywords" content="web comic, comic, cartoon, sci fi, satire, parody, science fiction, action, software industry, pulp, nerd, geek">
+<meta name="DC.Subject" content="web comic, comic, cartoon, sci fi, science fiction, satire, parody action, software industry">
+<meta name="page-topic" content="Science Fiction Web-Comic">
+<meta http-equiv="content-type" content="text/html;charset=UTF-8">`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
 	}
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.AddNameValueMetas(google_data)
-	txt := hdw.Render()
-
-	expected := `<!doctype html>
-<html lang="en"><head><meta name="The Name or Title Here"><meta description="This is the page description"><meta image="http,//www.example.com/image.jpg"></head><body></body></html>`
+}
 
+func TestMetaTags(t *testing.T) {
+	m := newMetaTags()
+	m.property("og:title", "Title Here")
+	m.name("twitter:card", "summary_large_image")
+	m.itemprop("image", "http://example.com/image.jpg")
+	m.link("canonical", "http://www.example.com/")
+
+	expected := `<meta property="og:title" content="Title Here">
+<meta name="twitter:card" content="summary_large_image">
+<meta itemprop="image" content="http://example.com/image.jpg">
+<link rel="canonical" href="http://www.example.com/">`
+	txt := string(m.Render())
 	if txt != expected {
 		t.Error(fe(expected, txt))
 	}
 }
 
-func TestOpenGraph(t *testing.T) {
-	og_data := []string{
-		"og:title", "Title Here",
-		"og:type", "article",
-		"og:url", "http://www.example.com/",
-		"og:image", "http://example.com/image.jpg",
-		"og:description", "Description Here",
-		"og:site_name", "Site Name, i.e. Moz",
-		"article:published_time", "2013-09-17T05:59:00+01:00",
-		"article:modified_time", "2013-09-16T19:08:47+01:00",
-		"article:section", "Article Section",
-		"article:tag", "Article Tag",
-		"fb:admins", "Facebook numberic ID",
+func TestNarrativePage(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	p := comic.NewPage("#85 Test", "", "/2017/04/19/85-Test", "http://localhost/DevAbode_0085.png", "", "III")
+	n := comic.NewPage("#86 Test", "", "/2017/04/26/86-Test", "http://localhost/DevAbode_0086.png", "", "III")
+	p.SetRels(nil, nil, n, n)
+	h := NewNarrativePageHtml(p)
+	h.SetPlaceholder("#7f7f7f", "data:image/png;base64,AAAA")
+	txt := h.writePage(newDefaultTheme())
+
+	expected := []string{
+		`<a href="/2017/04/26/86-Test"><img src="http://localhost/DevAbode_0085.png" width="800" height="1334" alt="" style="background:#7f7f7f url(data:image/png;base64,AAAA) center/cover no-repeat"></a>`,
+		`<link rel="preload" as="image" href="http://localhost/DevAbode_0086.png">`,
+		`<link rel="canonical" href="/2017/04/19/85-Test">`,
+		`<nav><a rel="next" title="#86 Test" href="/2017/04/26/86-Test">next &gt;</a><a rel="last" title="#86 Test" href="/2017/04/26/86-Test">newest &gt;</a></nav>`,
+		`this.page.title = "#85 Test"`,
 	}
-
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.AddNameValueMetas(og_data)
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head><meta og:title="Title Here"><meta og:type="article"><meta og:url="http://www.example.com/"><meta og:image="http://example.com/image.jpg"><meta og:description="Description Here"><meta og:site_name="Site Name, i.e. Moz"><meta article:published_time="2013-09-17T05:59:00+01:00"><meta article:modified_time="2013-09-16T19:08:47+01:00"><meta article:section="Article Section"><meta article:tag="Article Tag"><meta fb:admins="Facebook numberic ID"></head><body></body></html>`
-
-	if txt != expected {
-		t.Error(fe(expected, txt))
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
 	}
 }
 
-func TestStandardMeta(t *testing.T) {
-	hdw := newHtmlDocWrapper().(*htmlDocWrapper)
-	hdw.addStandardMeta()
-	txt := hdw.Render()
-	expected := `<!doctype html>
-<html lang="en"><head><meta viewport="width=device-width, initial-scale=1.0"><meta robots="index,follow"><meta author="Ingmar Drewing"><meta publisher="Ingmar Drewing"><meta keywords="web comic, comic, cartoon, sci fi, satire, parody, science fiction, action, software industry, pulp, nerd, geek"><meta DC.Subject="web comic, comic, cartoon, sci fi, science fiction, satire, parody action, software industry"><meta page-topic="Science Fiction Web-Comic"><meta http-equiv="content-type" content="text/html;charset=UTF-8"></head><body></body></html>`
-	if txt != expected {
-		t.Error(fe(expected, txt))
-	}
+func renderTestPage() string {
+	config.ReadDirect("testdata/gomic.yaml")
+	dh := NewDataHtml("", "/about.html")
+	dh.SetMenu(newMenu(readContent()))
+	return dh.writePage(newDefaultTheme(), "About")
 }
 
 func fe(expected string, actual string) string {
@@ -0,0 +1,42 @@
+package fs
+
+import (
+	"github.com/ingmardrewing/gomic/config"
+	"github.com/ingmardrewing/gomic/img"
+)
+
+// iconLink is a favicon or touch icon linked from the page head.
+type iconLink struct {
+	Rel   string
+	Sizes string
+	Href  string
+}
+
+func iconUrl(i img.Icon) string {
+	return config.Servedrootpath() + "/icons/" + i.Filename()
+}
+
+// iconLinks links the icons rendered by writeIcons, both follow the
+// size list of the img package.
+func iconLinks() []iconLink {
+	links := []iconLink{}
+	for _, i := range img.Icons {
+		if len(i.Rel) > 0 {
+			links = append(links, iconLink{i.Rel, i.Sizes(), iconUrl(i)})
+		}
+	}
+	return links
+}
+
+// feedImage is the configured feed image or else the 32px favicon.
+func feedImage() string {
+	if len(config.FeedImage()) > 0 {
+		return config.FeedImage()
+	}
+	for _, i := range img.Icons {
+		if i.Prefix == "favicon" && i.Size == 32 {
+			return config.SiteUrl() + "/icons/" + i.Filename()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package fs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+type jsonFeedDoc struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageUrl string           `json:"home_page_url"`
+	FeedUrl     string           `json:"feed_url"`
+	NextUrl     string           `json:"next_url,omitempty"`
+	Description string           `json:"description"`
+	Icon        string           `json:"icon"`
+	Language    string           `json:"language"`
+	Authors     []jsonFeedAuthor `json:"authors"`
+	Hubs        []jsonFeedHub    `json:"hubs,omitempty"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedHub struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+type jsonFeedItem struct {
+	Id            string   `json:"id"`
+	Url           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHtml   string   `json:"content_html"`
+	Summary       string   `json:"summary"`
+	Image         string   `json:"image"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags"`
+}
+
+func (f *feed) jsonFeed() string {
+	doc := jsonFeedDoc{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.title,
+		HomePageUrl: f.link,
+		FeedUrl:     f.url("feed.json"),
+		Description: f.description,
+		Icon:        f.image,
+		Language:    f.language,
+		Authors:     []jsonFeedAuthor{{f.author}},
+		Items:       []jsonFeedItem{},
+	}
+	if next := f.relPath("next"); len(next) > 0 {
+		doc.NextUrl = feedUrl(next, "feed.json")
+	}
+	if hub := config.WebSubHub(); len(hub) > 0 {
+		doc.Hubs = []jsonFeedHub{{"WebSub", hub}}
+	}
+	for _, i := range f.items {
+		doc.Items = append(doc.Items, jsonFeedItem{
+			Id:            i.url,
+			Url:           i.url,
+			Title:         i.title,
+			ContentHtml:   i.content,
+			Summary:       i.title,
+			Image:         i.imageUrl,
+			DatePublished: i.published.Format(time.RFC3339),
+			Tags:          []string{i.act},
+		})
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(data) + "\n"
+}
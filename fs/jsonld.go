@@ -0,0 +1,171 @@
+package fs
+
+import (
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// The json-ld types below describe pages with the schema.org
+// vocabulary. The theme renders each of them into its own
+// <script type="application/ld+json"> block.
+
+const schemaOrg = "https://schema.org"
+
+type ldThing struct {
+	Context     string `json:"@context,omitempty"`
+	Type        string `json:"@type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Url         string `json:"url,omitempty"`
+}
+
+type ldWebSite struct {
+	ldThing
+	InLanguage string    `json:"inLanguage,omitempty"`
+	Publisher  *ldPerson `json:"publisher,omitempty"`
+}
+
+type ldPerson struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type ldBreadcrumbList struct {
+	ldThing
+	ItemListElement []ldListItem `json:"itemListElement"`
+}
+
+type ldListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item"`
+}
+
+type ldComicSeries struct {
+	ldThing
+	Author     *ldPerson `json:"author,omitempty"`
+	InLanguage string    `json:"inLanguage,omitempty"`
+}
+
+type ldComicIssue struct {
+	ldThing
+	IssueNumber int            `json:"issueNumber,omitempty"`
+	IsPartOf    *ldComicSeries `json:"isPartOf,omitempty"`
+	Author      *ldPerson      `json:"author,omitempty"`
+	Artist      *ldPerson      `json:"artist,omitempty"`
+}
+
+type ldComicStory struct {
+	ldThing
+	Headline      string         `json:"headline,omitempty"`
+	Position      int            `json:"position,omitempty"`
+	DatePublished string         `json:"datePublished,omitempty"`
+	InLanguage    string         `json:"inLanguage,omitempty"`
+	Author        *ldPerson      `json:"author,omitempty"`
+	Artist        *ldPerson      `json:"artist,omitempty"`
+	Image         *ldImageObject `json:"image,omitempty"`
+	ThumbnailUrl  string         `json:"thumbnailUrl,omitempty"`
+	IsPartOf      *ldComicIssue  `json:"isPartOf,omitempty"`
+}
+
+type ldImageObject struct {
+	Type         string `json:"@type"`
+	ContentUrl   string `json:"contentUrl"`
+	Url          string `json:"url,omitempty"`
+	Caption      string `json:"caption,omitempty"`
+	ThumbnailUrl string `json:"thumbnailUrl,omitempty"`
+}
+
+func ldAuthor() *ldPerson {
+	return &ldPerson{"Person", config.Author()}
+}
+
+func homeUrl() string {
+	return config.Servedrootpath() + "/"
+}
+
+func newLdWebSite() *ldWebSite {
+	return &ldWebSite{
+		ldThing{schemaOrg, "WebSite", config.SiteTitle(), config.SiteDescription(), homeUrl()},
+		config.Language(),
+		ldAuthor(),
+	}
+}
+
+// newLdBreadcrumbs lists the way from the home page to the current
+// page, each crumb given as name and url.
+func newLdBreadcrumbs(crumbs ...[2]string) *ldBreadcrumbList {
+	b := &ldBreadcrumbList{ldThing{Context: schemaOrg, Type: "BreadcrumbList"}, []ldListItem{}}
+	b.ItemListElement = append(b.ItemListElement, ldListItem{"ListItem", 1, config.SiteTitle(), homeUrl()})
+	for i, c := range crumbs {
+		b.ItemListElement = append(b.ItemListElement, ldListItem{"ListItem", i + 2, c[0], c[1]})
+	}
+	return b
+}
+
+func newLdComicSeries() *ldComicSeries {
+	return &ldComicSeries{
+		ldThing{Type: "ComicSeries", Name: config.SiteTitle(), Description: config.SiteDescription(), Url: homeUrl()},
+		ldAuthor(),
+		config.Language(),
+	}
+}
+
+// newLdComicIssue describes an act as an issue of the comic series,
+// numbered by the order of the acts.
+func newLdComicIssue(c *comic.Comic, a *comic.Act) *ldComicIssue {
+	number := 0
+	for i, ca := range c.GetActs() {
+		if ca.GetName() == a.GetName() {
+			number = i + 1
+		}
+	}
+	return &ldComicIssue{
+		ldThing:     ldThing{Type: "ComicIssue", Name: a.GetName(), Url: a.GetPath()},
+		IssueNumber: number,
+		IsPartOf:    newLdComicSeries(),
+		Author:      ldAuthor(),
+		Artist:      ldAuthor(),
+	}
+}
+
+// newLdComicStory describes a page, as part of its act if the comic
+// is known.
+func newLdComicStory(c *comic.Comic, p *comic.Page) *ldComicStory {
+	s := &ldComicStory{
+		ldThing:       ldThing{Context: schemaOrg, Type: "ComicStory", Name: p.GetTitle(), Description: p.GetDescription(), Url: p.GetPath()},
+		Headline:      p.GetTitle(),
+		DatePublished: p.GetPublishDate().Format(time.RFC3339),
+		InLanguage:    config.Language(),
+		Author:        ldAuthor(),
+		Artist:        ldAuthor(),
+		Image: &ldImageObject{
+			Type:         "ImageObject",
+			ContentUrl:   p.GetImgUrl(),
+			Url:          p.GetImgUrl(),
+			Caption:      p.GetTitle(),
+			ThumbnailUrl: p.GetThumnailUrl(),
+		},
+		ThumbnailUrl: p.GetThumnailUrl(),
+	}
+	if c != nil {
+		s.Position = c.GetPageNumber(p)
+		if a := c.GetAct(p.GetAct()); a != nil {
+			s.IsPartOf = newLdComicIssue(c, a)
+		}
+	}
+	if s.IsPartOf == nil {
+		s.IsPartOf = &ldComicIssue{ldThing: ldThing{Type: "ComicIssue", Name: p.GetAct()}, IsPartOf: newLdComicSeries()}
+	}
+	return s
+}
+
+// newLdActIssue is the top level description of an act page.
+func newLdActIssue(c *comic.Comic, a *comic.Act) *ldComicIssue {
+	i := newLdComicIssue(c, a)
+	i.Context = schemaOrg
+	return i
+}
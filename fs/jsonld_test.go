@@ -0,0 +1,186 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// schemaType is a type of the schema.org subset in
+// testdata/schemaorg.json, with its own properties and those a
+// consumer like a search engine requires.
+type schemaType struct {
+	Parent     string   `json:"parent"`
+	Properties []string `json:"properties"`
+	Required   []string `json:"required"`
+}
+
+func readSchema(t *testing.T) map[string]schemaType {
+	data, err := ioutil.ReadFile("testdata/schemaorg.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := map[string]schemaType{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func allowed(schema map[string]schemaType, typ string, property string) bool {
+	for st, ok := schema[typ]; ok; st, ok = schema[st.Parent] {
+		for _, p := range st.Properties {
+			if p == property {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validate checks an object and the objects nested in it against the
+// schema, it returns the types found.
+func validate(t *testing.T, schema map[string]schemaType, obj map[string]interface{}) []string {
+	typ, _ := obj["@type"].(string)
+	st, ok := schema[typ]
+	if !ok {
+		t.Errorf("Expected a schema.org type, but got %q", typ)
+		return nil
+	}
+	for _, r := range st.Required {
+		if v, ok := obj[r]; !ok || v == "" {
+			t.Errorf("Expected %s to have %s, but got %v", typ, r, obj)
+		}
+	}
+	types := []string{typ}
+	for k, v := range obj {
+		if strings.HasPrefix(k, "@") {
+			continue
+		}
+		if !allowed(schema, typ, k) {
+			t.Errorf("Expected no %s in %s", k, typ)
+		}
+		nested := []interface{}{v}
+		if list, ok := v.([]interface{}); ok {
+			nested = list
+		}
+		for _, n := range nested {
+			if o, ok := n.(map[string]interface{}); ok {
+				types = append(types, validate(t, schema, o)...)
+			}
+		}
+	}
+	return types
+}
+
+// jsonLdBlocks parses the json-ld script blocks of a page.
+func jsonLdBlocks(t *testing.T, page string) []map[string]interface{} {
+	start := `<script type="application/ld+json">`
+	blocks := []map[string]interface{}{}
+	for _, part := range strings.Split(page, start)[1:] {
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(part[:strings.Index(part, "</script>")]), &obj); err != nil {
+			t.Fatal(err)
+		}
+		if obj["@context"] != schemaOrg {
+			t.Errorf("Expected the schema.org context, but got %v", obj["@context"])
+		}
+		blocks = append(blocks, obj)
+	}
+	return blocks
+}
+
+func validatePage(t *testing.T, page string) string {
+	schema := readSchema(t)
+	types := []string{}
+	for _, b := range jsonLdBlocks(t, page) {
+		types = append(types, validate(t, schema, b)...)
+	}
+	return strings.Join(types, " ")
+}
+
+func ldComic() *comic.Comic {
+	pages := feedPages(3)
+	for i, p := range pages {
+		p.ImgUrl = fmt.Sprintf("http://localhost/DevAbode_%04d.png", i+1)
+	}
+	pages[2].Act = "Act II"
+	c := comic.NewComic(pages)
+	c.ConnectPages()
+	return &c
+}
+
+func TestNarrativePageJsonLd(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c := ldComic()
+	h := NewNarrativePageHtml(c.GetPages()[2])
+	h.SetComic(c)
+	page := h.writePage(newDefaultTheme())
+
+	types := validatePage(t, page)
+	for _, e := range []string{"WebSite", "BreadcrumbList", "ComicStory", "ImageObject", "ComicIssue", "ComicSeries"} {
+		if !strings.Contains(types, e) {
+			t.Error(fe(e, types))
+		}
+	}
+
+	blocks := jsonLdBlocks(t, page)
+	story := blocks[2]
+	issue := story["isPartOf"].(map[string]interface{})
+	if story["position"] != 3.0 || issue["name"] != "Act II" || issue["issueNumber"] != 2.0 {
+		t.Errorf("Expected page 3 in the second act, but got %v", story)
+	}
+
+	crumbs := []string{}
+	for _, i := range blocks[1]["itemListElement"].([]interface{}) {
+		crumbs = append(crumbs, i.(map[string]interface{})["name"].(string))
+	}
+	expected := "DevAbo.de > Act II > #3"
+	if strings.Join(crumbs, " > ") != expected {
+		t.Error(fe(expected, strings.Join(crumbs, " > ")))
+	}
+}
+
+func TestActPageJsonLd(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c := ldComic()
+	page := NewActHtml(c, c.GetActs()[1]).writePage(newDefaultTheme())
+
+	types := validatePage(t, page)
+	for _, e := range []string{"WebSite", "BreadcrumbList", "ComicIssue", "ComicSeries"} {
+		if !strings.Contains(types, e) {
+			t.Error(fe(e, types))
+		}
+	}
+}
+
+func TestJsonLdOnEveryPage(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	th := newDefaultTheme()
+	pages := map[string]string{"archive": NewArchiveHtml().writePage(th)}
+	for _, cp := range readContent() {
+		pages[cp.Path] = NewContentPageHtml(cp).writePage(th, cp.Title)
+	}
+	for name, page := range pages {
+		types := validatePage(t, page)
+		if !strings.HasPrefix(types, "WebSite") || !strings.Contains(types, "BreadcrumbList") {
+			t.Errorf("Expected WebSite and BreadcrumbList on %s, but got %s", name, types)
+		}
+	}
+}
+
+func TestJsonLdEscapesHostileTitles(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	for _, title := range hostileTitles {
+		page := NewNarrativePageHtml(hostilePage(title)).writePage(newDefaultTheme())
+		story := jsonLdBlocks(t, page)[2]
+		if story["name"] != title {
+			t.Error(fe(title, story["name"].(string)))
+		}
+	}
+}
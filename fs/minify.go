@@ -0,0 +1,210 @@
+package fs
+
+import (
+	"path"
+	"strings"
+)
+
+// minify shrinks html, css and js by the extension of name and
+// returns any other content unchanged. The minifiers are deliberately
+// conservative, they only drop comments and whitespace that can't
+// change how a page renders or a script runs.
+func minify(name string, content string) string {
+	switch path.Ext(name) {
+	case ".html":
+		return minifyHtml(content)
+	case ".css":
+		return minifyCss(content)
+	case ".js":
+		return minifyJs(content)
+	}
+	return content
+}
+
+// rawTextTags are html elements whose content is kept as it is.
+var rawTextTags = []string{"pre", "textarea", "script", "style"}
+
+// minifyHtml removes comments and collapses whitespace between and
+// inside text. A whitespace run containing a line break becomes a
+// line break, any other run a single space, so inline elements keep
+// their spacing.
+func minifyHtml(s string) string {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		switch {
+		case strings.HasPrefix(s[i:], "<!--[if"):
+			end := indexFrom(s, "-->", i)
+			b.WriteString(s[i:end])
+			i = end
+		case strings.HasPrefix(s[i:], "<!--"):
+			i = indexFrom(s, "-->", i)
+			if out := b.String(); len(out) > 0 && isSpace(out[len(out)-1]) {
+				for i < len(s) && isSpace(s[i]) {
+					i++
+				}
+			}
+		case s[i] == '<':
+			end := tagEnd(s, i)
+			tag := s[i:end]
+			b.WriteString(tag)
+			i = end
+			if name := rawTextTag(tag); len(name) > 0 {
+				close := indexFold(s[i:], "</"+name)
+				if close < 0 {
+					close = len(s) - i
+				}
+				b.WriteString(s[i : i+close])
+				i += close
+			}
+		case isSpace(s[i]):
+			j := i
+			for j < len(s) && isSpace(s[j]) {
+				j++
+			}
+			if strings.ContainsAny(s[i:j], "\n\r") {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+			i = j
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// minifyCss removes comments and whitespace around punctuation,
+// strings are copied verbatim.
+func minifyCss(s string) string {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := stringEnd(s, i)
+			b.WriteString(s[i:end])
+			i = end
+		case strings.HasPrefix(s[i:], "/*"):
+			i = indexFrom(s, "*/", i)
+		case isSpace(c):
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			out := b.String()
+			if len(out) == 0 || i == len(s) ||
+				strings.IndexByte("{};,>(:", out[len(out)-1]) >= 0 ||
+				strings.IndexByte("{};,>)", s[i]) >= 0 {
+				continue
+			}
+			b.WriteByte(' ')
+		case c == '}':
+			out := b.String()
+			if strings.HasSuffix(out, ";") {
+				b.Reset()
+				b.WriteString(out[:len(out)-1])
+			}
+			b.WriteByte(c)
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// minifyJs drops indentation, blank lines and comments on lines of
+// their own. Line breaks are kept so automatic semicolon insertion
+// still works; multi-line template literals aren't supported.
+func minifyJs(s string) string {
+	lines := []string{}
+	comment := false
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if comment {
+			end := strings.Index(l, "*/")
+			if end < 0 {
+				continue
+			}
+			comment = false
+			l = strings.TrimSpace(l[end+2:])
+		}
+		if strings.HasPrefix(l, "/*") {
+			end := strings.Index(l[2:], "*/")
+			if end < 0 {
+				comment = true
+				continue
+			}
+			l = strings.TrimSpace(l[end+4:])
+		}
+		if len(l) == 0 || strings.HasPrefix(l, "//") {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indexFrom returns the index following the first occurrence of sep
+// at or after i, or the end of s.
+func indexFrom(s string, sep string, i int) int {
+	j := strings.Index(s[i:], sep)
+	if j < 0 {
+		return len(s)
+	}
+	return i + j + len(sep)
+}
+
+func indexFold(s string, sep string) int {
+	return strings.Index(strings.ToLower(s), sep)
+}
+
+// tagEnd returns the index following the tag starting at i, quoted
+// attribute values may contain a >.
+func tagEnd(s string, i int) int {
+	var quote byte
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case quote != 0:
+			if s[j] == quote {
+				quote = 0
+			}
+		case s[j] == '"' || s[j] == '\'':
+			quote = s[j]
+		case s[j] == '>':
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func rawTextTag(tag string) string {
+	name := strings.ToLower(strings.TrimLeft(tag, "<"))
+	for _, t := range rawTextTags {
+		if strings.HasPrefix(name, t) && len(name) > len(t) &&
+			strings.IndexByte(" >\t\n", name[len(t)]) >= 0 {
+			return t
+		}
+	}
+	return ""
+}
+
+func stringEnd(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case s[i]:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,107 @@
+package fs
+
+import (
+	"testing"
+)
+
+func TestMinifyHtml(t *testing.T) {
+	html := `<!doctype html>
+<html>
+  <!-- navigation -->
+  <body>
+    <a href="/a">first</a> <a href="/b">second</a>
+    <p title="a > b">keep   the
+      text</p>
+  </body>
+</html>`
+
+	expected := `<!doctype html>
+<html>
+<body>
+<a href="/a">first</a> <a href="/b">second</a>
+<p title="a > b">keep the
+text</p>
+</body>
+</html>`
+	txt := minifyHtml(html)
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestMinifyHtmlKeepsRawText(t *testing.T) {
+	html := `<pre>
+  indented   code
+</pre>
+<script>
+  var s = "  <!-- not a comment -->  ";
+</script>
+<!--[if IE]><p>old</p><![endif]-->`
+
+	txt := minifyHtml(html)
+	if txt != html {
+		t.Error(fe(html, txt))
+	}
+}
+
+func TestMinifyCss(t *testing.T) {
+	css := `/* header */
+header .home {
+	display: block;
+	background: url( "a b.png" ) no-repeat;
+	content: "  spaced  ";
+}
+@media screen and (max-width: 600px) {
+	a:hover , a > b { width: calc(100% - 2px); }
+}
+`
+
+	expected := `header .home{display:block;background:url("a b.png") no-repeat;content:"  spaced  "}@media screen and (max-width:600px){a:hover,a>b{width:calc(100% - 2px)}}`
+	txt := minifyCss(css)
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestMinifyJs(t *testing.T) {
+	js := `/**
+ * plugin
+ */
+(function($){
+  // defaults
+  var a = 1
+
+  var b = "// not a comment";
+  /* inline */ var c = 2;
+})(jQuery);
+`
+
+	expected := `(function($){
+var a = 1
+var b = "// not a comment";
+var c = 2;
+})(jQuery);`
+	txt := minifyJs(js)
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestMinifyIsIdempotent(t *testing.T) {
+	th := newDefaultTheme()
+	for _, name := range []string{"css/style.css", "js/script.js"} {
+		once := minify(name, th.read("assets/"+name))
+		twice := minify(name, once)
+		if once != twice {
+			t.Error(fe(once, twice))
+		}
+	}
+}
+
+func TestMinifyLeavesOtherFiles(t *testing.T) {
+	xml := "<rss>\n  <channel/>\n</rss>"
+	txt := minify("feed/rss.xml", xml)
+	if txt != xml {
+		t.Error(fe(xml, txt))
+	}
+}
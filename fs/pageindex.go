@@ -0,0 +1,54 @@
+package fs
+
+import (
+	"encoding/json"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// pageIndex lists the acts and pages for the jump to page form of the
+// narrative pages, it is written to /pages.json.
+type pageIndex struct {
+	Acts  []pageIndexAct   `json:"acts"`
+	Pages []pageIndexEntry `json:"pages"`
+}
+
+type pageIndexAct struct {
+	Name  string `json:"name"`
+	Url   string `json:"url"`
+	First int    `json:"first"`
+}
+
+type pageIndexEntry struct {
+	Title string `json:"t"`
+	Url   string `json:"u"`
+	Act   int    `json:"a"`
+}
+
+func pageIndexUrl() string {
+	return config.Servedrootpath() + "/pages.json"
+}
+
+// newPageIndex numbers the pages from 1 in reading order, the position
+// of a page in the pages list is its number minus one.
+func newPageIndex(c *comic.Comic) *pageIndex {
+	pi := &pageIndex{[]pageIndexAct{}, []pageIndexEntry{}}
+	acts := map[string]int{}
+	for i, a := range c.GetActs() {
+		acts[a.GetName()] = i
+		pi.Acts = append(pi.Acts, pageIndexAct{a.GetName(), a.GetPath(), c.GetPageNumber(a.GetPages()[0])})
+	}
+	for _, p := range c.GetPages() {
+		pi.Pages = append(pi.Pages, pageIndexEntry{p.GetTitle(), p.GetPath(), acts[p.GetAct()]})
+	}
+	return pi
+}
+
+func (pi *pageIndex) json() string {
+	data, err := json.Marshal(pi)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
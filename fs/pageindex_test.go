@@ -0,0 +1,61 @@
+package fs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func TestPageIndex(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	pages := feedPages(4)
+	pages[1].Act = "Act II"
+	pages[2].Act = "Act II"
+	c := comic.NewComic(pages)
+
+	var pi pageIndex
+	if err := json.Unmarshal([]byte(newPageIndex(&c).json()), &pi); err != nil {
+		t.Fatal(err)
+	}
+	if len(pi.Acts) != 2 || pi.Acts[1].Name != "Act II" || pi.Acts[1].First != 2 || pi.Acts[1].Url != "/acts/act-ii.html" {
+		t.Errorf("Expected Act II to start with page 2, but got %v", pi.Acts)
+	}
+	acts := []string{}
+	for _, p := range pi.Pages {
+		acts = append(acts, pi.Acts[p.Act].Name)
+	}
+	expected := "Act I, Act II, Act II, Act I"
+	if strings.Join(acts, ", ") != expected {
+		t.Error(fe(expected, strings.Join(acts, ", ")))
+	}
+	if pi.Pages[3].Url != "/2017/01/04/page-4" || pi.Pages[3].Title != "#4" {
+		t.Errorf("Expected page 4 last, but got %v", pi.Pages[3])
+	}
+}
+
+func TestNarrativePageNavigation(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c := comic.NewComic(feedPages(3))
+	c.ConnectPages()
+	th := newDefaultTheme()
+	txt := NewNarrativePageHtml(c.GetPages()[1]).writePage(th)
+
+	js := th.readerScript()
+	expected := []string{
+		`<link rel="start" href="/2017/01/01/page-1">
+<link rel="prev" href="/2017/01/01/page-1">
+<link rel="next" href="/2017/01/03/page-3">
+<link rel="last" href="/2017/01/03/page-3">`,
+		`<form class="jump" data-index="/pages.json" hidden>`,
+		`<script src="/` + js.path() + `" integrity="` + integrityAttr(js) + `" type="text/javascript"></script>`,
+		`<p class="bookmark" hidden></p>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package fs
+
+import (
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// FeedUrls are the feeds new pages show up in, the main feed and the
+// feeds of their acts in each format. They are the topics the WebSub
+// hub is notified about, so they match the self links of the feeds.
+func FeedUrls(c *comic.Comic, pages []*comic.Page) []string {
+	paths := []string{"/feed/"}
+	for _, a := range changedActs(c, pages) {
+		paths = append(paths, actFeedPath(a))
+	}
+	urls := []string{}
+	for _, path := range paths {
+		for _, format := range feedFormats {
+			urls = append(urls, feedUrl(path, format.name))
+		}
+	}
+	return urls
+}
+
+// ChangedUrls are the pages changed by publishing new pages: the new
+// pages, the page before them now linking to them, the home page, the
+// archive and the pages of their acts.
+func ChangedUrls(c *comic.Comic, pages []*comic.Page) []string {
+	urls := []string{}
+	if len(pages) == 0 {
+		return urls
+	}
+	for _, p := range pages {
+		urls = append(urls, siteUrl(p.FSPath()))
+	}
+	if n := c.GetPageNumber(pages[0]); n > 1 {
+		urls = append(urls, siteUrl(c.GetPages()[n-2].FSPath()))
+	}
+	urls = append(urls, siteUrl("/"), siteUrl("/archive.html"))
+	for _, a := range changedActs(c, pages) {
+		urls = append(urls, siteUrl(a.FSPath()))
+	}
+	return urls
+}
+
+func changedActs(c *comic.Comic, pages []*comic.Page) []*comic.Act {
+	acts := []*comic.Act{}
+	seen := map[string]bool{}
+	for _, p := range pages {
+		if !seen[p.GetAct()] {
+			seen[p.GetAct()] = true
+			acts = append(acts, c.GetAct(p.GetAct()))
+		}
+	}
+	return acts
+}
+
+// indexNowKeyPath is where IndexNow looks for the key to verify the
+// pings are sent by the site.
+func indexNowKeyPath() string {
+	return "/" + config.IndexNowKey() + ".txt"
+}
+
+// IndexNowKeyUrl is the url of the key file.
+func IndexNowKeyUrl() string {
+	return siteUrl(indexNowKeyPath())
+}
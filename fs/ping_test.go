@@ -0,0 +1,89 @@
+package fs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// readPingConfig configures a hub and an IndexNow key until the test
+// is done.
+func readPingConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "gomic-yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("ping:\n  websubhub: https://hub.example.com/\n  indexnowkey: 0123abcd\n")
+	f.Close()
+	config.ReadDirect(f.Name())
+	t.Cleanup(func() { config.ReadDirect("testdata/gomic.yaml") })
+}
+
+func TestFeedsAdvertiseHub(t *testing.T) {
+	f := testFeed()
+	readPingConfig(t)
+
+	expected := `<atom:link href="https://hub.example.com/" rel="hub"></atom:link>`
+	if txt := f.rss(); !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+	expected = `<link href="https://hub.example.com/" rel="hub"></link>`
+	if txt := f.atom(); !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+	var doc jsonFeedDoc
+	json.Unmarshal([]byte(f.jsonFeed()), &doc)
+	if len(doc.Hubs) != 1 || doc.Hubs[0].Type != "WebSub" || doc.Hubs[0].Url != "https://hub.example.com/" {
+		t.Errorf("Expected the WebSub hub, but got %v", doc.Hubs)
+	}
+}
+
+func TestFeedsWithoutHub(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	if txt := testFeed().rss(); strings.Contains(txt, `rel="hub"`) {
+		t.Errorf("Expected no hub in %s", txt)
+	}
+}
+
+func pingComic() (*comic.Comic, []*comic.Page) {
+	pages := feedPages(4)
+	pages[2].Act = "Act II"
+	pages[3].Act = "Act II"
+	c := comic.NewComic(pages)
+	return &c, pages[2:]
+}
+
+func TestFeedUrls(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c, newPages := pingComic()
+	expected := "/feed/rss.xml /feed/atom.xml /feed/feed.json /feed/acts/act-ii/rss.xml /feed/acts/act-ii/atom.xml /feed/acts/act-ii/feed.json"
+	if actual := strings.Join(FeedUrls(c, newPages), " "); actual != expected {
+		t.Error(fe(expected, actual))
+	}
+}
+
+func TestChangedUrls(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c, newPages := pingComic()
+	expected := "/2017/01/03/page-3 /2017/01/04/page-4 /2017/01/02/page-2 / /archive.html /acts/act-ii.html"
+	if actual := strings.Join(ChangedUrls(c, newPages), " "); actual != expected {
+		t.Error(fe(expected, actual))
+	}
+	if urls := ChangedUrls(c, nil); len(urls) != 0 {
+		t.Errorf("Expected no urls without new pages, but got %v", urls)
+	}
+}
+
+func TestIndexNowKeyUrl(t *testing.T) {
+	readPingConfig(t)
+	expected := "/0123abcd.txt"
+	if actual := IndexNowKeyUrl(); actual != expected {
+		t.Error(fe(expected, actual))
+	}
+}
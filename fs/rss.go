@@ -1,96 +1,147 @@
 package fs
 
 import (
-	"fmt"
+	"encoding/xml"
+	"time"
 
-	"github.com/ingmardrewing/gomic/comic"
 	"github.com/ingmardrewing/gomic/config"
 )
 
-type rss struct {
-	comic *comic.Comic
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Dc      string     `xml:"xmlns:dc,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Sy      string     `xml:"xmlns:sy,attr"`
+	Media   string     `xml:"xmlns:media,attr"`
+	Channel rssChannel `xml:"channel"`
 }
 
-func newRss(comic *comic.Comic) *rss {
-	return &rss{comic}
+type rssChannel struct {
+	Title           string    `xml:"title"`
+	Image           rssImage  `xml:"image"`
+	AtomLinks       []xmlLink `xml:"atom:link"`
+	Link            string    `xml:"link"`
+	Description     string    `xml:"description"`
+	LastBuildDate   string    `xml:"lastBuildDate"`
+	Language        string    `xml:"language"`
+	UpdatePeriod    string    `xml:"sy:updatePeriod"`
+	UpdateFrequency int       `xml:"sy:updateFrequency"`
+	Generator       string    `xml:"generator"`
+	Items           []rssItem `xml:"item"`
 }
 
-func (r *rss) RssItem(p *comic.Page) string {
-	title := p.GetTitle()
-	url := p.GetPath()
-	pubDate := p.GetDateFromFSPath()
-	act := p.GetAct()
-	description := p.GetTitle()
-	content := fmt.Sprintf(`<img src="%s">`, p.GetImgUrl())
-	thumbnailUrl := p.GetThumnailUrl()
-	imageUrl := p.GetImgUrl()
-	imageName := p.GetImageFilename()
-	return fmt.Sprintf(rssItem, title, url, pubDate, act, url, description, content, thumbnailUrl, imageUrl, imageName, thumbnailUrl)
+type rssImage struct {
+	Url         string `xml:"url"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Width       int    `xml:"width"`
+	Height      int    `xml:"height"`
+	Description string `xml:"description"`
 }
 
-func (r *rss) RssItems() string {
-	pgs := r.comic.Get10LastComicPagesNewestFirst()
-	h := ""
-	for _, p := range pgs {
-		h += r.RssItem(p)
-	}
-	return h
+// xmlLink is an atom:link, used by rss and atom feeds.
+type xmlLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type rssItem struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	PubDate     string       `xml:"pubDate"`
+	Creator     string       `xml:"dc:creator"`
+	Category    string       `xml:"category"`
+	Guid        rssGuid      `xml:"guid"`
+	Description string       `xml:"description"`
+	Content     string       `xml:"content:encoded"`
+	Thumbnail   mediaUrl     `xml:"media:thumbnail"`
+	Media       mediaContent `xml:"media:content"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type mediaUrl struct {
+	Url string `xml:"url,attr"`
 }
 
-func (r *rss) Rss() string {
-	date := DateNow()
-	items := r.RssItems()
-	relSelf := config.Servedrootpath() + "/feed/rss.xml"
-	return fmt.Sprintf(rssTemplate, relSelf, date, items)
+type mediaContent struct {
+	Url       string     `xml:"url,attr"`
+	Medium    string     `xml:"medium,attr"`
+	Title     mediaTitle `xml:"media:title"`
+	Thumbnail mediaUrl   `xml:"media:thumbnail"`
 }
 
-var rssTemplate = `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"
-	xmlns:content="http://purl.org/rss/1.0/modules/content/"
-	xmlns:wfw="http://wellformedweb.org/CommentAPI/"
-	xmlns:dc="http://purl.org/dc/elements/1.1/"
-	xmlns:atom="http://www.w3.org/2005/Atom"
-	xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"
-	xmlns:slash="http://purl.org/rss/1.0/modules/slash/"
-	xmlns:media="http://search.yahoo.com/mrss/"
-	>
+type mediaTitle struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
 
-<channel>
-	<title>DevAbo.de</title>
-    <image>
-      <url>https://devabo.de/favicon-32x32.png</url>
-      <title>DevAbo.de</title>
-      <link>https://devabo.de</link>
-      <width>32</width>
-      <height>32</height>
-      <description>A science-fiction webcomic about the lives of software developers in the far, funny and dystopian future</description>
-    </image>
-	<atom:link href="%s" rel="self" type="application/rss+xml" />
-	<link>https://DevAbo.de</link>
-	<description>A science-fiction webcomic about the lives of software developers in the far, funny and dystopian future</description>
-	<lastBuildDate>%s</lastBuildDate>
-	<language>en-US</language>
-	<sy:updatePeriod>weekly</sy:updatePeriod>
-	<sy:updateFrequency>1</sy:updateFrequency>
-	<generator>https://github.com/ingmardrewing/gomic</generator>
-%s
-	</channel>
-</rss>
-`
+func (f *feed) rss() string {
+	doc := rssDoc{
+		Version: "2.0",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		Dc:      "http://purl.org/dc/elements/1.1/",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Sy:      "http://purl.org/rss/1.0/modules/syndication/",
+		Media:   "http://search.yahoo.com/mrss/",
+		Channel: rssChannel{
+			Title:           f.title,
+			Image:           rssImage{f.image, f.title, f.link, 32, 32, f.description},
+			AtomLinks:       f.xmlLinks("rss.xml", "application/rss+xml"),
+			Link:            f.link,
+			Description:     f.description,
+			LastBuildDate:   DateNow(),
+			Language:        f.language,
+			UpdatePeriod:    "weekly",
+			UpdateFrequency: 1,
+			Generator:       "https://github.com/ingmardrewing/gomic",
+		},
+	}
+	for _, i := range f.items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       i.title,
+			Link:        i.url,
+			PubDate:     i.published.Format(time.RFC1123Z),
+			Creator:     f.author,
+			Category:    i.act,
+			Guid:        rssGuid{false, i.url + "/index.html"},
+			Description: i.description,
+			Content:     i.content,
+			Thumbnail:   mediaUrl{i.thumbnailUrl},
+			Media: mediaContent{
+				i.imageUrl,
+				"image",
+				mediaTitle{"plain", i.imageName},
+				mediaUrl{i.thumbnailUrl}},
+		})
+	}
+	return marshalFeed(doc)
+}
 
-var rssItem = `  <item>
-    <title>%s</title>
-    <link>%s</link>
-    <pubDate>%s</pubDate>
-    <dc:creator><![CDATA[Ingmar Drewing]]></dc:creator>
-    <category><![CDATA[%s]]></category>
-    <guid>%s/index.html</guid>
-    <description><![CDATA[%s]]></description>
-    <content:encoded><![CDATA[%s]]></content:encoded>
+// xmlLinks returns the self link of the feed in the given format,
+// followed by the links to the other pages of a paged feed and the
+// WebSub hub if there is one.
+func (f *feed) xmlLinks(name string, mimeType string) []xmlLink {
+	links := []xmlLink{{f.url(name), "self", mimeType}}
+	for _, r := range f.rels {
+		links = append(links, xmlLink{feedUrl(r.path, name), r.rel, mimeType})
+	}
+	if hub := config.WebSubHub(); len(hub) > 0 {
+		links = append(links, xmlLink{hub, "hub", ""})
+	}
+	return links
+}
 
-    <media:thumbnail url="%s" />
-    <media:content url="%s" medium="image">
-      <media:title type="html">%s</media:title>
-      <media:thumbnail url="%s" />
-    </media:content>
-  </item>
-`
+func marshalFeed(v interface{}) string {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return xml.Header + string(data) + "\n"
+}
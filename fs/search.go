@@ -0,0 +1,148 @@
+package fs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// The search index is split into a shard per act. Shards are
+// published under fingerprinted names, like the theme assets, so a
+// build only writes the shards of acts whose pages changed and
+// browsers may cache the others forever. The manifest at
+// /search/index.json lists the current shards.
+
+const searchIndexVersion = 1
+
+type searchManifest struct {
+	Version int              `json:"version"`
+	Shards  []searchShardRef `json:"shards"`
+}
+
+type searchShardRef struct {
+	Act       string `json:"act"`
+	Url       string `json:"url"`
+	Integrity string `json:"integrity"`
+}
+
+// searchShard holds the pages of an act and an inverted index from
+// each term to the positions of the pages containing it.
+type searchShard struct {
+	Docs  []searchDoc      `json:"docs"`
+	Terms map[string][]int `json:"terms"`
+}
+
+type searchDoc struct {
+	Title string `json:"t"`
+	Url   string `json:"u"`
+	Thumb string `json:"i"`
+	Act   string `json:"a"`
+}
+
+type searchIndex struct {
+	shards []*asset
+	acts   []string
+}
+
+func newSearchIndex(c *comic.Comic) *searchIndex {
+	si := &searchIndex{[]*asset{}, []string{}}
+	for _, a := range c.GetActs() {
+		si.shards = append(si.shards, newSearchShard(a))
+		si.acts = append(si.acts, a.GetName())
+	}
+	return si
+}
+
+func newSearchShard(a *comic.Act) *asset {
+	s := searchShard{[]searchDoc{}, map[string][]int{}}
+	for i, p := range a.GetPages() {
+		s.Docs = append(s.Docs, searchDoc{p.GetTitle(), p.GetPath(), p.GetThumnailUrl(), p.GetAct()})
+		for _, t := range searchTerms(searchText(p)) {
+			s.Terms[t] = append(s.Terms[t], i)
+		}
+	}
+	return newAsset("search/"+a.GetSlug()+".json", marshalSearch(s))
+}
+
+// searchText is what a page is found by: its title, description,
+// transcript, act and tags.
+func searchText(p *comic.Page) string {
+	parts := []string{p.GetTitle(), p.Description, p.GetTranscript(), p.GetAct()}
+	return strings.Join(append(parts, p.GetTags()...), " ")
+}
+
+// searchTerms splits a text into lower case words of at least two
+// letters or digits, each listed once. The search page tokenizes
+// queries the same way.
+func searchTerms(txt string) []string {
+	words := strings.FieldsFunc(strings.ToLower(txt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, w := range words {
+		if len([]rune(w)) < 2 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+func (si *searchIndex) manifest() string {
+	m := searchManifest{searchIndexVersion, []searchShardRef{}}
+	for i, s := range si.shards {
+		m.Shards = append(m.Shards, searchShardRef{si.acts[i], config.Servedrootpath() + "/" + s.path(), s.integrity()})
+	}
+	return marshalSearch(m)
+}
+
+// write saves the manifest and the shards that aren't there yet, and
+// removes the shards no longer listed.
+func (si *searchIndex) write(root string) error {
+	dir := filepath.Join(root, "search")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	current := map[string]bool{"index.json": true}
+	for _, s := range si.shards {
+		current[s.filename()] = true
+		if _, err := os.Stat(filepath.Join(root, s.path())); err == nil {
+			continue
+		}
+		log.Println("Writing search shard: ", s.path())
+		if err := ioutil.WriteFile(filepath.Join(root, s.path()), []byte(s.content), 0644); err != nil {
+			return err
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		name := strings.TrimSuffix(strings.TrimSuffix(f.Name(), ".gz"), ".br")
+		if !f.IsDir() && !current[name] {
+			log.Println("removing outdated search shard", f.Name())
+			if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "index.json"), []byte(si.manifest()), 0644)
+}
+
+func marshalSearch(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
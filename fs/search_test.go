@@ -0,0 +1,120 @@
+package fs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func searchComic() *comic.Comic {
+	pages := feedPages(3)
+	pages[0].Transcript = "The ROBOT appears, says: Beep!"
+	pages[1].Tags = []string{"spaceship", "Müller"}
+	pages[2].Act = "Act II"
+	c := comic.NewComic(pages)
+	return &c
+}
+
+func TestSearchTerms(t *testing.T) {
+	txt := strings.Join(searchTerms("The robot's ROBOT, a Müller-Lüdenscheidt 42!"), " ")
+	expected := "the robot müller lüdenscheidt 42"
+	if txt != expected {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestSearchShard(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c := searchComic()
+	shard := newSearchShard(c.GetActs()[0])
+
+	var s searchShard
+	if err := json.Unmarshal([]byte(shard.content), &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Docs) != 2 || s.Docs[1].Title != "#2" || s.Docs[1].Act != "Act I" {
+		t.Errorf("Expected the two pages of Act I, but got %v", s.Docs)
+	}
+	for term, expected := range map[string]int{"robot": 0, "beep": 0, "müller": 1, "spaceship": 1} {
+		if docs := s.Terms[term]; len(docs) != 1 || docs[0] != expected {
+			t.Errorf("Expected %s to find page %d, but got %v", term, expected, docs)
+		}
+	}
+	if docs := s.Terms["act"]; len(docs) != 2 {
+		t.Errorf("Expected the act name to find both pages, but got %v", docs)
+	}
+}
+
+func TestSearchManifest(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	si := newSearchIndex(searchComic())
+
+	var m searchManifest
+	if err := json.Unmarshal([]byte(si.manifest()), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != searchIndexVersion || len(m.Shards) != 2 {
+		t.Fatalf("Expected two shards, but got %v", m)
+	}
+	if m.Shards[1].Act != "Act II" || m.Shards[1].Url != "/"+si.shards[1].path() || !strings.HasPrefix(m.Shards[1].Integrity, "sha384-") {
+		t.Errorf("Expected the fingerprinted shard of Act II, but got %v", m.Shards[1])
+	}
+}
+
+func TestSearchIndexWritesChangedShardsOnly(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	root, err := ioutil.TempDir("", "search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	c := searchComic()
+	if err := newSearchIndex(c).write(root); err != nil {
+		t.Fatal(err)
+	}
+	unchanged := filepath.Join(root, newSearchIndex(c).shards[0].path())
+	ioutil.WriteFile(unchanged, []byte("kept"), 0644)
+
+	c.GetPages()[2].Title = "#3 Changed"
+	si := newSearchIndex(c)
+	if err := si.write(root); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, _ := ioutil.ReadFile(unchanged); string(b) != "kept" {
+		t.Errorf("Expected the shard of Act I not to be rewritten, but got %s", b)
+	}
+	files, _ := filepath.Glob(filepath.Join(root, "search", "*.json"))
+	names := []string{}
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	expected := strings.Join([]string{si.shards[0].filename(), si.shards[1].filename(), "index.json"}, " ")
+	if strings.Join(names, " ") != expected {
+		t.Error(fe(expected, strings.Join(names, " ")))
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	th := newDefaultTheme()
+	txt := NewSearchHtml().writePage(th)
+
+	js := th.searchScript()
+	expected := []string{
+		`<form id="search" role="search" action="/search.html" data-index="/search/index.json">`,
+		`<script src="/` + js.path() + `" integrity="` + integrityAttr(js) + `" type="text/javascript"></script>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(txt, e) {
+			t.Error(fe(e, txt))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package fs
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// maxSitemapUrls is the limit of urls in a single sitemap, beyond it
+// the urls are split into several sitemaps listed by a sitemap index.
+var maxSitemapUrls = 50000
+
+type sitemapUrlset struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Image   string       `xml:"xmlns:image,attr"`
+	Urls    []sitemapUrl `xml:"url"`
+}
+
+type sitemapUrl struct {
+	Loc     string         `xml:"loc"`
+	Lastmod string         `xml:"lastmod,omitempty"`
+	Images  []sitemapImage `xml:"image:image"`
+}
+
+type sitemapImage struct {
+	Loc     string `xml:"image:loc"`
+	Caption string `xml:"image:caption,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name         `xml:"sitemapindex"`
+	Xmlns    string           `xml:"xmlns,attr"`
+	Sitemaps []sitemapPointer `xml:"sitemap"`
+}
+
+type sitemapPointer struct {
+	Loc     string `xml:"loc"`
+	Lastmod string `xml:"lastmod,omitempty"`
+}
+
+type sitemap struct {
+	comic   *comic.Comic
+	content []*contentPage
+}
+
+func newSitemap(c *comic.Comic, content []*contentPage) *sitemap {
+	return &sitemap{c, content}
+}
+
+func lastmod(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// urls lists the narrative pages with their images, the act pages,
+// the archive and the static pages.
+func (s *sitemap) urls() []sitemapUrl {
+	urls := []sitemapUrl{}
+	for _, p := range s.comic.GetPages() {
+		urls = append(urls, sitemapUrl{
+			p.GetPath(),
+			lastmod(p.GetPublishDate()),
+			[]sitemapImage{{p.GetImgUrl(), p.GetTitle()}}})
+	}
+	for _, a := range s.comic.GetActs() {
+		pages := a.GetPages()
+		urls = append(urls, sitemapUrl{
+			a.GetPath(),
+			lastmod(pages[len(pages)-1].GetPublishDate()),
+			nil})
+	}
+	if last := s.comic.LastPage(); last != nil {
+		urls = append(urls, sitemapUrl{
+			config.Servedrootpath() + "/archive.html",
+			lastmod(last.GetPublishDate()),
+			nil})
+	}
+	for _, cp := range s.content {
+		urls = append(urls, sitemapUrl{siteUrl(cp.Path), "", nil})
+	}
+	return urls
+}
+
+// files returns the sitemap files by their names, either a single
+// sitemap.xml or, with too many urls, a sitemap.xml index pointing
+// to sitemap-1.xml, sitemap-2.xml and so on.
+func (s *sitemap) files() map[string]string {
+	urls := s.urls()
+	if len(urls) <= maxSitemapUrls {
+		return map[string]string{"sitemap.xml": marshalSitemap(newUrlset(urls))}
+	}
+
+	files := map[string]string{}
+	index := sitemapIndex{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for i := 0; i*maxSitemapUrls < len(urls); i++ {
+		end := (i + 1) * maxSitemapUrls
+		if end > len(urls) {
+			end = len(urls)
+		}
+		chunk := urls[i*maxSitemapUrls : end]
+		name := fmt.Sprintf("sitemap-%d.xml", i+1)
+		files[name] = marshalSitemap(newUrlset(chunk))
+		index.Sitemaps = append(index.Sitemaps, sitemapPointer{
+			config.Servedrootpath() + "/" + name,
+			newestLastmod(chunk)})
+	}
+	files["sitemap.xml"] = marshalSitemap(index)
+	return files
+}
+
+func newUrlset(urls []sitemapUrl) sitemapUrlset {
+	return sitemapUrlset{
+		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		Image: "http://www.google.com/schemas/sitemap-image/1.1",
+		Urls:  urls}
+}
+
+func newestLastmod(urls []sitemapUrl) string {
+	newest := ""
+	for _, u := range urls {
+		if u.Lastmod > newest {
+			newest = u.Lastmod
+		}
+	}
+	return newest
+}
+
+func marshalSitemap(v interface{}) string {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return xml.Header + string(data) + "\n"
+}
+
+func robotsTxt() string {
+	return fmt.Sprintf("User-agent: *\nAllow: /\n\nSitemap: %s/sitemap.xml\n", config.Servedrootpath())
+}
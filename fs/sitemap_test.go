@@ -0,0 +1,115 @@
+package fs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func sitemapComic() *comic.Comic {
+	c := comic.NewComic([]*comic.Page{
+		comic.NewPage("#1 Start", "", "/2017/04/12/1-Start", "http://localhost/DevAbode_0001.png", "", "Act I"),
+		comic.NewPage("#2 Tom & Jerry", "", "/2017/04/19/2-Tom-Jerry", "http://localhost/DevAbode_0002.png", "", "Act I"),
+		comic.NewPage("#3 Return", "", "/2017/04/26/3-Return", "http://localhost/DevAbode_0003.png", "", "Act II"),
+	})
+	return &c
+}
+
+func TestSitemap(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	content := []*contentPage{{Title: "About", Path: "/about.html"}}
+	files := newSitemap(sitemapComic(), content).files()
+
+	if len(files) != 1 {
+		t.Fatalf("Expected a single sitemap, but got %d files", len(files))
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+  <url>
+    <loc>/2017/04/12/1-Start</loc>
+    <lastmod>2017-04-12</lastmod>
+    <image:image>
+      <image:loc>http://localhost/DevAbode_0001.png</image:loc>
+      <image:caption>#1 Start</image:caption>
+    </image:image>
+  </url>
+  <url>
+    <loc>/2017/04/19/2-Tom-Jerry</loc>
+    <lastmod>2017-04-19</lastmod>
+    <image:image>
+      <image:loc>http://localhost/DevAbode_0002.png</image:loc>
+      <image:caption>#2 Tom &amp; Jerry</image:caption>
+    </image:image>
+  </url>
+  <url>
+    <loc>/2017/04/26/3-Return</loc>
+    <lastmod>2017-04-26</lastmod>
+    <image:image>
+      <image:loc>http://localhost/DevAbode_0003.png</image:loc>
+      <image:caption>#3 Return</image:caption>
+    </image:image>
+  </url>
+  <url>
+    <loc>/acts/act-i.html</loc>
+    <lastmod>2017-04-19</lastmod>
+  </url>
+  <url>
+    <loc>/acts/act-ii.html</loc>
+    <lastmod>2017-04-26</lastmod>
+  </url>
+  <url>
+    <loc>/archive.html</loc>
+    <lastmod>2017-04-26</lastmod>
+  </url>
+  <url>
+    <loc>/about.html</loc>
+  </url>
+</urlset>
+`
+	if files["sitemap.xml"] != expected {
+		t.Error(fe(expected, files["sitemap.xml"]))
+	}
+}
+
+func TestSitemapIndex(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	max := maxSitemapUrls
+	maxSitemapUrls = 4
+	defer func() { maxSitemapUrls = max }()
+
+	files := newSitemap(sitemapComic(), []*contentPage{}).files()
+
+	if len(files) != 3 {
+		t.Fatalf("Expected an index and 2 sitemaps, but got %d files", len(files))
+	}
+	if strings.Count(files["sitemap-1.xml"], "<url>") != 4 || strings.Count(files["sitemap-2.xml"], "<url>") != 2 {
+		t.Errorf("Expected 4 and 2 urls, but got %s and %s", files["sitemap-1.xml"], files["sitemap-2.xml"])
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>/sitemap-1.xml</loc>
+    <lastmod>2017-04-26</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>/sitemap-2.xml</loc>
+    <lastmod>2017-04-26</lastmod>
+  </sitemap>
+</sitemapindex>
+`
+	if files["sitemap.xml"] != expected {
+		t.Error(fe(expected, files["sitemap.xml"]))
+	}
+}
+
+func TestRobotsTxt(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	expected := "User-agent: *\nAllow: /\n\nSitemap: /sitemap.xml\n"
+	if robotsTxt() != expected {
+		t.Error(fe(expected, robotsTxt()))
+	}
+}
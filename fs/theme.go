@@ -0,0 +1,147 @@
+package fs
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	iofs "io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+//go:embed theme/default
+var defaultThemeFiles embed.FS
+
+// theme holds the layouts, partials and assets the site is rendered
+// with. A theme directory configured with the theme key in gomic.yaml
+// replaces the embedded default theme as a whole, so a custom theme
+// is best started as a copy of fs/theme/default.
+type theme struct {
+	files   iofs.FS
+	base    *template.Template
+	layouts map[string]*template.Template
+	built   map[string]*asset
+}
+
+func newTheme() *theme {
+	if len(config.Theme()) > 0 {
+		return newThemeFromFiles(os.DirFS(config.Theme()))
+	}
+	return newDefaultTheme()
+}
+
+func newDefaultTheme() *theme {
+	sub, err := iofs.Sub(defaultThemeFiles, "theme/default")
+	if err != nil {
+		panic(err)
+	}
+	return newThemeFromFiles(sub)
+}
+
+// newThemeFromFiles parses the templates once. Each layout defines
+// the content block of the base layout, so it gets a copy of base and
+// partials of its own.
+func newThemeFromFiles(files iofs.FS) *theme {
+	base := template.Must(template.New("base").ParseFS(files, "layouts/base.html", "partials/*.html"))
+	names, err := iofs.Glob(files, "layouts/*.html")
+	if err != nil {
+		panic(err)
+	}
+	layouts := map[string]*template.Template{}
+	for _, n := range names {
+		name := strings.TrimSuffix(path.Base(n), ".html")
+		if name == "base" {
+			continue
+		}
+		layouts[name] = template.Must(template.Must(base.Clone()).ParseFS(files, n))
+	}
+	return &theme{files, base, layouts, map[string]*asset{}}
+}
+
+// render executes the base layout with the content block of the
+// given layout, e.g. narrative, archive or static.
+func (t *theme) render(layout string, data interface{}) string {
+	tmpl, ok := t.layouts[layout]
+	if !ok {
+		panic("no layout " + layout + " in theme")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "base", data); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// renderPartial executes a single partial, e.g. the one of a comments
+// provider.
+func (t *theme) renderPartial(name string, data interface{}) template.HTML {
+	var buf bytes.Buffer
+	if err := t.base.ExecuteTemplate(&buf, name, data); err != nil {
+		panic(err)
+	}
+	return template.HTML(buf.String())
+}
+
+// asset reads, minifies and fingerprints an asset on first use only.
+func (t *theme) asset(name string) *asset {
+	if a, ok := t.built[name]; ok {
+		return a
+	}
+	content := t.read("assets/" + name)
+	if config.Minify() {
+		content = minify(name, content)
+	}
+	a := newAsset(name, content)
+	t.built[name] = a
+	return a
+}
+
+func (t *theme) stylesheet() *asset {
+	return t.asset("css/style.css")
+}
+
+func (t *theme) script() *asset {
+	return t.asset("js/script.js")
+}
+
+func (t *theme) readerScript() *asset {
+	return t.asset("js/reader.js")
+}
+
+func (t *theme) consentScript() *asset {
+	return t.asset("js/consent.js")
+}
+
+func (t *theme) commentsScript() *asset {
+	return t.asset("js/comments.js")
+}
+
+func (t *theme) searchScript() *asset {
+	return t.asset("js/search.js")
+}
+
+// assets are the fingerprinted css and js files of the theme.
+func (t *theme) assets() []*asset {
+	return []*asset{t.stylesheet(), t.script(), t.readerScript(), t.searchScript(), t.commentsScript(), t.consentScript()}
+}
+
+// images are the files below assets/imgs the stylesheet refers to,
+// they are copied to /imgs/ as they are.
+func (t *theme) images() []string {
+	names, err := iofs.Glob(t.files, "assets/imgs/*")
+	if err != nil {
+		panic(err)
+	}
+	return names
+}
+
+func (t *theme) read(name string) string {
+	b, err := iofs.ReadFile(t.files, name)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,61 @@
+// Self-hosted comments: GET endpoint?thread=<id> answers with the
+// comments of a thread as a json list of {id, parent, author, date,
+// text}, a POST of {thread, parent, author, text} to the endpoint
+// adds one.
+(function(){
+  var section = document.getElementById('comments');
+  if (!section) { return; }
+  var endpoint = section.getAttribute('data-endpoint');
+  var thread = section.getAttribute('data-thread');
+  var list = section.querySelector('ol.comments');
+  var form = section.querySelector('form.comment');
+
+  function item(c){
+    var li = document.createElement('li');
+    li.id = 'comment-' + c.id;
+    var head = document.createElement('p');
+    head.className = 'comment-meta';
+    head.textContent = c.author + ', ' + new Date(c.date).toLocaleDateString();
+    var body = document.createElement('p');
+    body.textContent = c.text;
+    li.appendChild(head);
+    li.appendChild(body);
+    return li;
+  }
+
+  function show(comments){
+    var byId = {};
+    list.textContent = '';
+    comments.forEach(function(c){
+      var li = item(c);
+      byId[c.id] = li;
+      var parent = c.parent && byId[c.parent];
+      if (parent) {
+        var ol = parent.querySelector('ol') || parent.appendChild(document.createElement('ol'));
+        ol.appendChild(li);
+      } else {
+        list.appendChild(li);
+      }
+    });
+  }
+
+  function load(){
+    return fetch(endpoint + '?thread=' + encodeURIComponent(thread))
+      .then(function(r){ return r.json(); })
+      .then(show);
+  }
+
+  form.addEventListener('submit', function(e){
+    e.preventDefault();
+    fetch(endpoint, {
+      method: 'POST',
+      headers: {'Content-Type': 'application/json'},
+      body: JSON.stringify({thread: thread, parent: '', author: form.author.value, text: form.text.value})
+    }).then(function(r){
+      if (r.ok) { form.text.value = ''; }
+      return load();
+    });
+  });
+
+  load().then(function(){ form.hidden = false; });
+})();
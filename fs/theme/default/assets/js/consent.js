@@ -0,0 +1,118 @@
+// Consent management: content of the page wrapped in
+// <template data-consent="category"> is only activated once the
+// reader opted in to its category. The choice is kept in
+// localStorage, no cookie is set.
+(function(){
+  var key = 'gomic-consent';
+  var banner = document.getElementById('consent');
+  if (!banner) { return; }
+  var form = banner.querySelector('form');
+
+  function read(){
+    try {
+      return JSON.parse(localStorage.getItem(key)) || {};
+    } catch (e) {
+      return {};
+    }
+  }
+
+  function write(choice){
+    try {
+      localStorage.setItem(key, JSON.stringify(choice));
+    } catch (e) {
+      // Without localStorage the banner is shown on every page.
+    }
+  }
+
+  function gated(){
+    return Array.prototype.slice.call(document.querySelectorAll('template[data-consent]'));
+  }
+
+  // used lists the categories of the content on this page.
+  function used(){
+    var categories = [];
+    gated().forEach(function(t){
+      var c = t.getAttribute('data-consent');
+      if (categories.indexOf(c) < 0) { categories.push(c); }
+    });
+    return categories;
+  }
+
+  // script recreates a script, scripts taken from a template would
+  // not run. Scripts loaded from elsewhere keep their order.
+  function script(s){
+    var n = document.createElement('script');
+    Array.prototype.slice.call(s.attributes).forEach(function(a){
+      n.setAttribute(a.name, a.value);
+    });
+    if (n.src) {
+      n.async = false;
+      if (n.integrity) { n.crossOrigin = 'anonymous'; }
+    }
+    n.text = s.text;
+    return n;
+  }
+
+  function activate(choice){
+    gated().forEach(function(t){
+      var category = t.getAttribute('data-consent');
+      if (choice[category] !== true) { return; }
+      var content = document.importNode(t.content, true);
+      Array.prototype.slice.call(content.querySelectorAll('script')).forEach(function(s){
+        s.parentNode.replaceChild(script(s), s);
+      });
+      t.parentNode.replaceChild(content, t);
+      Array.prototype.slice.call(document.querySelectorAll('[data-consent-placeholder="' + category + '"]')).forEach(function(p){
+        p.parentNode.removeChild(p);
+      });
+    });
+  }
+
+  function open(){
+    var choice = read();
+    var categories = used();
+    Array.prototype.slice.call(banner.querySelectorAll('label[data-category]')).forEach(function(l){
+      var c = l.getAttribute('data-category');
+      l.hidden = categories.indexOf(c) < 0;
+      l.querySelector('input').checked = choice[c] === true;
+    });
+    banner.hidden = false;
+  }
+
+  // decide saves the choice, taking back a consent needs a reload to
+  // unload the scripts.
+  function decide(value){
+    var before = read();
+    var choice = read();
+    used().forEach(function(c){
+      choice[c] = value === null ? form.elements[c].checked : value;
+    });
+    write(choice);
+    banner.hidden = true;
+    var revoked = Object.keys(before).some(function(c){ return before[c] === true && choice[c] !== true; });
+    if (revoked) {
+      location.reload();
+    } else {
+      activate(choice);
+    }
+  }
+
+  form.addEventListener('submit', function(e){
+    e.preventDefault();
+    decide(null);
+  });
+  banner.querySelector('[data-consent-action=accept]').addEventListener('click', function(){ decide(true); });
+  banner.querySelector('[data-consent-action=reject]').addEventListener('click', function(){ decide(false); });
+  Array.prototype.slice.call(document.querySelectorAll('[data-consent-action=open]')).forEach(function(a){
+    a.addEventListener('click', function(e){
+      e.preventDefault();
+      open();
+    });
+  });
+
+  var choice = read();
+  activate(choice);
+  if (used().some(function(c){ return choice[c] === undefined; })) {
+    open();
+  }
+})();
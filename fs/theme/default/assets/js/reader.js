@@ -0,0 +1,95 @@
+// Reader navigation of the narrative pages: arrow keys and swipes
+// follow the rel=prev/next links of the head, the jump form is fed
+// by the page index and the last page read is kept as bookmark.
+(function(){
+  var bookmarkKey = 'gomic-bookmark';
+
+  function rel(name){
+    var l = document.querySelector('link[rel=' + name + ']');
+    return l ? l.getAttribute('href') : null;
+  }
+
+  function go(name){
+    var href = rel(name);
+    if (href) { location.href = href; }
+  }
+
+  document.addEventListener('keydown', function(e){
+    if (e.altKey || e.ctrlKey || e.metaKey || e.shiftKey) { return; }
+    if (/^(INPUT|SELECT|TEXTAREA)$/.test(e.target.tagName) || e.target.isContentEditable) { return; }
+    if (e.key === 'ArrowLeft') { go('prev'); }
+    if (e.key === 'ArrowRight') { go('next'); }
+  });
+
+  var image = document.querySelector('main img');
+  var touch = null;
+  if (image) {
+    image.addEventListener('touchstart', function(e){
+      touch = e.changedTouches[0];
+    }, {passive: true});
+    image.addEventListener('touchend', function(e){
+      if (!touch) { return; }
+      var dx = e.changedTouches[0].clientX - touch.clientX;
+      var dy = e.changedTouches[0].clientY - touch.clientY;
+      touch = null;
+      if (Math.abs(dx) > 50 && Math.abs(dx) > 2 * Math.abs(dy)) {
+        go(dx < 0 ? 'next' : 'prev');
+      }
+    });
+  }
+
+  var form = document.querySelector('form.jump');
+  if (form) {
+    fetch(form.getAttribute('data-index'))
+      .then(function(r){ return r.json(); })
+      .then(function(index){ fillJump(form, index); });
+  }
+
+  function fillJump(form, index){
+    var act = form.querySelector('select[name=act]');
+    var page = form.querySelector('input[name=page]');
+    index.acts.forEach(function(a, i){
+      var o = document.createElement('option');
+      o.value = i;
+      o.textContent = a.name;
+      act.appendChild(o);
+    });
+    page.max = index.pages.length;
+    act.addEventListener('change', function(){
+      page.value = index.acts[act.value].first;
+    });
+    form.addEventListener('submit', function(e){
+      e.preventDefault();
+      var p = index.pages[parseInt(page.value, 10) - 1];
+      if (p) {
+        location.href = p.u;
+      } else if (act.value !== '') {
+        location.href = index.acts[act.value].url;
+      }
+    });
+    form.hidden = false;
+  }
+
+  // The front page shows the newest page under another url than its
+  // canonical one, only pages read under their own url are bookmarked.
+  var canonical = rel('canonical');
+  if (!canonical) { return; }
+  var front = new URL(canonical, location.href).pathname.replace(/\/$/, '') !== location.pathname.replace(/\/(index\.html)?$/, '');
+  try {
+    if (!front) {
+      localStorage.setItem(bookmarkKey, JSON.stringify({url: canonical, title: document.title}));
+      return;
+    }
+    var bookmark = JSON.parse(localStorage.getItem(bookmarkKey) || 'null');
+    var offer = document.querySelector('p.bookmark');
+    if (bookmark && offer && bookmark.url !== canonical) {
+      var a = document.createElement('a');
+      a.href = bookmark.url;
+      a.textContent = 'Continue where you left off: ' + bookmark.title;
+      offer.appendChild(a);
+      offer.hidden = false;
+    }
+  } catch (e) {
+    // localStorage is unavailable, e.g. in private browsing.
+  }
+})();
@@ -0,0 +1,182 @@
+// staticFormHandler offers a form in the container once the display
+// condition is met, e.g. the newsletter sign up after scrolling down,
+// and sends the form fields as json to the url.
+function staticFormHandler(container, options){
+  var defaults = {
+      name: 'staticFormHandler',
+      fields: {},
+      url: "",
+      intro_txt: "<h3>Want to get new pages via e-mail?</h3><p>Sign up to receive new DevAbo.de-pages via e-mail:<br>Just enter your e-mail address in the field below and click on &bdquo;Yes, sign me up&ldquo;.</p>",
+      confirmation_txt: "<p>Thank you! You are almost ready - just click on the confirmation link sent to you via e-mail.</p>",
+      error_txt: "Sorry, couldn't connect to the server. If you try again later, it might work.",
+      display_condition: function(){ return false; },
+      ask_only_once: true
+    };
+
+  function opt(field){
+    return options[field] || defaults[field];
+  }
+
+  function element(html){
+    var t = document.createElement('template');
+    t.innerHTML = html;
+    return t.content;
+  }
+
+  function button(label, onClick){
+    var b = document.createElement('a');
+    b.href = '#';
+    b.className = 'nl_button';
+    b.textContent = label;
+    b.addEventListener('click', function(e){
+      e.preventDefault();
+      onClick();
+    });
+    return b;
+  }
+
+  function field(name){
+    var d = document.createElement('div');
+    d.className = 'nl_field';
+    var l = document.createElement('label');
+    l.htmlFor = name;
+    var i = document.createElement('input');
+    i.type = 'text';
+    i.name = name;
+    i.id = name;
+    d.appendChild(l);
+    d.appendChild(i);
+    return d;
+  }
+
+  // The offer is remembered in localStorage instead of a cookie.
+  function alreadySeen(){
+    try {
+      return localStorage.getItem(opt('name')) === 'seen';
+    } catch (e) {
+      return false;
+    }
+  }
+
+  function setSeen(){
+    try {
+      localStorage.setItem(opt('name'), 'seen');
+    } catch (e) {
+      // Without localStorage the offer may be shown again.
+    }
+  }
+
+  function textContainer(){
+    return container.querySelector('.nl_text_container');
+  }
+
+  function showError(txt){
+    var c = textContainer();
+    var old = c.querySelector('.nl_error');
+    if (old) { old.parentNode.removeChild(old); }
+    var p = document.createElement('p');
+    p.className = 'nl_error';
+    p.textContent = txt;
+    c.insertBefore(p, c.firstChild);
+  }
+
+  function gatherData(){
+    var data = {};
+    Object.keys(opt('fields')).forEach(function(f){
+      data[f] = container.querySelector('#' + f).value;
+    });
+    return JSON.stringify(data);
+  }
+
+  function onSuccess(data){
+    if (data.Text === "address already registered") {
+      showError('This e-mail address is already registered.');
+    } else if (data.Text === "no address given") {
+      showError('Please enter a valid e-mail address.');
+    } else {
+      var c = textContainer();
+      c.textContent = '';
+      c.appendChild(element(opt('confirmation_txt')));
+      c.appendChild(button('Okay', close));
+    }
+  }
+
+  function sendData(){
+    fetch(opt('url'), {
+      method: 'PUT',
+      headers: {'Content-Type': 'application/json'},
+      body: gatherData()
+    }).then(function(r){
+      if (!r.ok) { throw new Error(r.statusText); }
+      return r.json();
+    }).then(onSuccess, function(){
+      showError('Please enter a valid e-mail address.');
+    });
+  }
+
+  function createForm(){
+    var f = document.createElement('form');
+    f.appendChild(element(opt('intro_txt')));
+    Object.keys(opt('fields')).forEach(function(name){
+      if (opt('fields')[name].type === 'input') {
+        f.appendChild(field(name));
+      }
+    });
+    f.appendChild(button('No, thanks.', close));
+    f.appendChild(button('Yes, sign me up!', sendData));
+    f.addEventListener('submit', function(e){
+      e.preventDefault();
+      sendData();
+    });
+    return f;
+  }
+
+  function showForm(){
+    container.classList.remove('nl_container_hidden');
+    var c = document.createElement('div');
+    c.className = 'nl_text_container';
+    c.appendChild(createForm());
+    container.appendChild(c);
+  }
+
+  function close(){
+    container.classList.add('nl_container_hidden');
+    container.textContent = '';
+  }
+
+  function trigger(){
+    if (opt('display_condition')()) {
+      window.removeEventListener('scroll', trigger);
+      showForm();
+      setSeen();
+    }
+  }
+
+  if (opt('ask_only_once') && alreadySeen()) { return; }
+  window.addEventListener('scroll', trigger, {passive: true});
+}
+
+document.addEventListener('DOMContentLoaded', function(){
+  var container = document.querySelector('.nl_container');
+  if (!container || !container.getAttribute('data-url')) { return; }
+  staticFormHandler(container, {
+    name: "devabodeNewsletterOffer",
+    fields: {
+      "Email": {
+        "type": "input"
+      }
+    },
+    url: container.getAttribute('data-url'),
+    display_condition: function(){ return window.pageYOffset > 200; }
+  });
+});
+
+// The service worker linked from the manifest keeps the site readable
+// offline.
+(function(){
+  var manifest = document.querySelector('link[rel="manifest"][data-service-worker]');
+  if (!manifest || !('serviceWorker' in navigator)) { return; }
+  window.addEventListener('load', function(){
+    navigator.serviceWorker.register(manifest.getAttribute('data-service-worker'));
+  });
+})();
@@ -0,0 +1,100 @@
+// Searches the static index written by gomic: /search/index.json
+// lists a shard per act, each shard maps the terms of its pages to
+// the positions of the pages in its docs.
+(function(){
+  var form = document.getElementById('search');
+  if (!form) { return; }
+  var input = form.querySelector('input[name=q]');
+  var results = document.getElementById('search-results');
+  var shards = null;
+
+  // terms splits a query like the generator splits the page texts.
+  function terms(txt){
+    var words = txt.toLowerCase().split(/[^\p{L}\p{N}]+/u);
+    return words.filter(function(w){ return Array.from(w).length >= 2; });
+  }
+
+  function load(){
+    if (shards) { return shards; }
+    shards = fetch(form.getAttribute('data-index'))
+      .then(function(r){ return r.json(); })
+      .then(function(m){
+        return Promise.all(m.shards.map(function(s){
+          return fetch(s.url, {integrity: s.integrity}).then(function(r){ return r.json(); });
+        }));
+      });
+    return shards;
+  }
+
+  // matches returns the positions of the docs having a term starting
+  // with the word.
+  function matches(shard, word){
+    var found = {};
+    Object.keys(shard.terms).forEach(function(t){
+      if (t.indexOf(word) === 0) {
+        shard.terms[t].forEach(function(i){ found[i] = true; });
+      }
+    });
+    return found;
+  }
+
+  function search(words, loaded){
+    var hits = [];
+    loaded.forEach(function(shard){
+      var found = null;
+      words.forEach(function(w){
+        var m = matches(shard, w);
+        if (found === null) { found = m; return; }
+        Object.keys(found).forEach(function(i){ if (!m[i]) { delete found[i]; } });
+      });
+      Object.keys(found || {}).forEach(function(i){ hits.push(shard.docs[i]); });
+    });
+    return hits;
+  }
+
+  function show(hits, query){
+    results.textContent = '';
+    if (hits.length === 0) {
+      var p = document.createElement('p');
+      p.textContent = 'Nothing found for "' + query + '".';
+      results.appendChild(p);
+      return;
+    }
+    var ul = document.createElement('ul');
+    ul.className = 'archive';
+    hits.forEach(function(d){
+      var li = document.createElement('li');
+      var a = document.createElement('a');
+      a.href = d.u;
+      var img = document.createElement('img');
+      img.src = d.i;
+      img.alt = d.t;
+      img.loading = 'lazy';
+      var span = document.createElement('span');
+      span.textContent = d.t + ' (' + d.a + ')';
+      a.appendChild(img);
+      a.appendChild(span);
+      li.appendChild(a);
+      ul.appendChild(li);
+    });
+    results.appendChild(ul);
+  }
+
+  function run(query){
+    var words = terms(query);
+    if (words.length === 0) { results.textContent = ''; return; }
+    load().then(function(loaded){ show(search(words, loaded), query); });
+  }
+
+  form.addEventListener('submit', function(e){
+    e.preventDefault();
+    history.replaceState(null, '', '?q=' + encodeURIComponent(input.value));
+    run(input.value);
+  });
+
+  var q = new URLSearchParams(location.search).get('q');
+  if (q) {
+    input.value = q;
+    run(q);
+  }
+})();
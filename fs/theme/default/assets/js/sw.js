@@ -0,0 +1,91 @@
+// Service worker for offline reading. GOMIC_SW is written by the
+// build: the cache version, the shell to precache and how many pages
+// to keep. All caches share the version, as cached pages reference
+// the fingerprinted css and js of the shell they were read with.
+var shellCache = 'gomic-shell-' + GOMIC_SW.version;
+var pagesCache = 'gomic-pages-' + GOMIC_SW.version;
+var imagesCache = 'gomic-images-' + GOMIC_SW.version;
+var current = [shellCache, pagesCache, imagesCache];
+
+self.addEventListener('install', function(e){
+  e.waitUntil(caches.open(shellCache).then(function(cache){
+    // a missing file, e.g. an icon never rendered, doesn't keep the
+    // worker from installing
+    return Promise.all(GOMIC_SW.shell.map(function(url){
+      return cache.add(url).catch(function(){});
+    }));
+  }).then(function(){
+    return self.skipWaiting();
+  }));
+});
+
+self.addEventListener('activate', function(e){
+  e.waitUntil(caches.keys().then(function(names){
+    return Promise.all(names.filter(function(n){
+      return n.indexOf('gomic-') === 0 && current.indexOf(n) < 0;
+    }).map(function(n){
+      return caches.delete(n);
+    }));
+  }).then(function(){
+    return self.clients.claim();
+  }));
+});
+
+// trim drops the oldest entries of a cache beyond max.
+function trim(name, max){
+  return caches.open(name).then(function(cache){
+    return cache.keys().then(function(keys){
+      return Promise.all(keys.slice(0, Math.max(keys.length - max, 0)).map(function(k){
+        return cache.delete(k);
+      }));
+    });
+  });
+}
+
+function store(name, max, request, response){
+  var copy = response.clone();
+  caches.open(name).then(function(cache){
+    return cache.delete(request).then(function(){
+      return cache.put(request, copy);
+    });
+  }).then(function(){
+    return trim(name, max);
+  });
+  return response;
+}
+
+// Pages are fetched from the network first, so new pages show up,
+// and served from the cache when offline.
+function networkFirst(request){
+  return fetch(request).then(function(response){
+    if (!response.ok) { return response; }
+    return store(pagesCache, GOMIC_SW.maxPages, request, response);
+  }).catch(function(){
+    return caches.match(request).then(function(cached){
+      return cached || Response.error();
+    });
+  });
+}
+
+// Images of pages never change, so a cached copy is always good.
+function cacheFirst(request){
+  return caches.match(request).then(function(cached){
+    return cached || fetch(request).then(function(response){
+      if (!response.ok && response.type !== 'opaque') { return response; }
+      return store(imagesCache, GOMIC_SW.maxPages * 2, request, response);
+    });
+  });
+}
+
+self.addEventListener('fetch', function(e){
+  var request = e.request;
+  if (request.method !== 'GET') { return; }
+  if (request.destination === 'image') {
+    e.respondWith(cacheFirst(request));
+    return;
+  }
+  if (new URL(request.url).origin !== self.location.origin) { return; }
+  e.respondWith(caches.match(request, {cacheName: shellCache}).then(function(cached){
+    return cached || networkFirst(request);
+  }));
+});
@@ -0,0 +1,139 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+	"github.com/ingmardrewing/gomic/img"
+)
+
+// The site can be installed as a web app: /manifest.webmanifest
+// describes it and the service worker at /sw.js keeps it readable
+// offline. The worker precaches the shell, the fingerprinted css and
+// js along with the icons, and caches pages and images as they are
+// read.
+
+// minOfflinePages is the least number of recently read pages kept
+// offline, more if the latest act is longer, so it can be read
+// without a connection.
+const minOfflinePages = 50
+
+type webManifest struct {
+	Name            string         `json:"name"`
+	ShortName       string         `json:"short_name"`
+	Description     string         `json:"description"`
+	Lang            string         `json:"lang"`
+	StartUrl        string         `json:"start_url"`
+	Scope           string         `json:"scope"`
+	Display         string         `json:"display"`
+	ThemeColor      string         `json:"theme_color"`
+	BackgroundColor string         `json:"background_color"`
+	Icons           []manifestIcon `json:"icons"`
+}
+
+type manifestIcon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes"`
+	Type    string `json:"type"`
+	Purpose string `json:"purpose"`
+}
+
+func manifestUrl() string {
+	return config.Servedrootpath() + "/manifest.webmanifest"
+}
+
+func serviceWorkerUrl() string {
+	return config.Servedrootpath() + "/sw.js"
+}
+
+// appData links the manifest and the service worker from the page
+// head.
+type appData struct {
+	ManifestUrl      string
+	ServiceWorkerUrl string
+	ThemeColor       string
+}
+
+func newAppData() appData {
+	return appData{manifestUrl(), serviceWorkerUrl(), config.ThemeColor()}
+}
+
+func newWebManifest() *webManifest {
+	icons := []manifestIcon{}
+	for _, i := range img.Icons {
+		if len(i.Purpose) > 0 {
+			icons = append(icons, manifestIcon{iconUrl(i), i.Sizes(), "image/png", i.Purpose})
+		}
+	}
+	return &webManifest{
+		Name:            config.SiteTitle(),
+		ShortName:       config.AppShortName(),
+		Description:     config.SiteDescription(),
+		Lang:            config.Language(),
+		StartUrl:        config.Servedrootpath() + "/",
+		Scope:           config.Servedrootpath() + "/",
+		Display:         "standalone",
+		ThemeColor:      config.ThemeColor(),
+		BackgroundColor: config.BackgroundColor(),
+		Icons:           icons,
+	}
+}
+
+func (m *webManifest) json() string {
+	data, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// serviceWorkerCnf is handed to the worker script of the theme.
+type serviceWorkerCnf struct {
+	Version  string   `json:"version"`
+	Shell    []string `json:"shell"`
+	MaxPages int      `json:"maxPages"`
+}
+
+// newServiceWorkerCnf versions the caches with the build manifest,
+// the list of shell files. As the css and js are fingerprinted, any
+// change to them yields a new version and the worker replaces the
+// outdated shell cache.
+func newServiceWorkerCnf(t *theme, c *comic.Comic) *serviceWorkerCnf {
+	shell := []string{manifestUrl()}
+	for _, a := range t.assets() {
+		shell = append(shell, config.Servedrootpath()+"/"+a.path())
+	}
+	for _, i := range img.Icons {
+		if len(i.Rel) > 0 {
+			shell = append(shell, iconUrl(i))
+		}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(shell, "\n")))
+	return &serviceWorkerCnf{fmt.Sprintf("%x", sum)[:10], shell, offlinePages(c)}
+}
+
+func offlinePages(c *comic.Comic) int {
+	last := c.LastPage()
+	if last == nil {
+		return minOfflinePages
+	}
+	if n := len(c.GetAct(last.GetAct()).GetPages()); n > minOfflinePages {
+		return n
+	}
+	return minOfflinePages
+}
+
+// serviceWorker is the worker script of the theme preceded by its
+// configuration. It is served unfingerprinted from the site root, as
+// browsers look for updates at the url it was registered with.
+func serviceWorker(t *theme, c *comic.Comic) string {
+	data, err := json.Marshal(newServiceWorkerCnf(t, c))
+	if err != nil {
+		panic(err)
+	}
+	return "var GOMIC_SW = " + string(data) + ";\n" + t.read("assets/js/sw.js")
+}
@@ -0,0 +1,104 @@
+package fs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func TestWebManifest(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	var m webManifest
+	if err := json.Unmarshal([]byte(newWebManifest().json()), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "DevAbo.de" || m.ShortName != "DevAbo.de" || m.StartUrl != "/" || m.Display != "standalone" || m.ThemeColor != "#FF8800" {
+		t.Errorf("Expected the manifest of DevAbo.de, but got %v", m)
+	}
+	if len(m.Icons) == 0 || m.Icons[0].Src != "/icons/android-icon-192x192.png" || m.Icons[0].Sizes != "192x192" {
+		t.Errorf("Expected the android icon first, but got %v", m.Icons)
+	}
+}
+
+func TestServiceWorkerPrecachesShell(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	th := newDefaultTheme()
+	c := comic.NewComic(feedPages(3))
+	sw := newServiceWorkerCnf(th, &c)
+
+	shell := strings.Join(sw.Shell, " ")
+	for _, a := range th.assets() {
+		if !strings.Contains(shell, "/"+a.path()) {
+			t.Error(fe("/"+a.path(), shell))
+		}
+	}
+	if sw.Shell[0] != "/manifest.webmanifest" || sw.MaxPages != minOfflinePages {
+		t.Errorf("Expected the manifest and %d pages, but got %v", minOfflinePages, sw)
+	}
+
+	js := serviceWorker(th, &c)
+	if !strings.HasPrefix(js, `var GOMIC_SW = {"version":"`+sw.Version+`"`) || !strings.Contains(js, "caches.open(shellCache)") {
+		t.Errorf("Expected the configured worker script, but got %s", js)
+	}
+}
+
+func TestServiceWorkerVersionFollowsAssets(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	c := comic.NewComic(feedPages(3))
+	th := newDefaultTheme()
+	files := themeFiles()
+	for _, name := range []string{"css/style.css", "js/script.js", "js/reader.js", "js/search.js", "js/comments.js", "js/consent.js"} {
+		files["assets/"+name] = &fstest.MapFile{Data: []byte(th.read("assets/" + name))}
+	}
+	before := newServiceWorkerCnf(newThemeFromFiles(files), &c).Version
+	if after := newServiceWorkerCnf(newThemeFromFiles(files), &c).Version; after != before {
+		t.Errorf("Expected an unchanged build to keep version %s, but got %s", before, after)
+	}
+
+	files["assets/css/style.css"] = &fstest.MapFile{Data: []byte("body{color:red}")}
+	if after := newServiceWorkerCnf(newThemeFromFiles(files), &c).Version; after == before {
+		t.Errorf("Expected a changed stylesheet to change version %s", before)
+	}
+}
+
+func TestServiceWorkerKeepsLatestAct(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	pages := feedPages(minOfflinePages + 10)
+	pages[0].Act = "Prologue"
+	c := comic.NewComic(pages)
+	expected := minOfflinePages + 9
+	if actual := offlinePages(&c); actual != expected {
+		t.Errorf("Expected %d, but got %d", expected, actual)
+	}
+}
+
+func TestHeadLinksManifest(t *testing.T) {
+	txt := renderTestPage()
+	expected := `<link rel="manifest" href="/manifest.webmanifest" data-service-worker="/sw.js">
+<meta name="theme-color" content="#FF8800">`
+	if !strings.Contains(txt, expected) {
+		t.Error(fe(expected, txt))
+	}
+}
+
+func TestIconLinksFollowSizeList(t *testing.T) {
+	config.ReadDirect("testdata/gomic.yaml")
+	links := iconLinks()
+	if len(links) != 13 {
+		t.Errorf("Expected 13 linked icons, but got %v", links)
+	}
+	var m webManifest
+	json.Unmarshal([]byte(newWebManifest().json()), &m)
+	expected := "/icons/android-icon-192x192.png any, /icons/android-icon-512x512.png any, /icons/maskable-icon-512x512.png maskable"
+	icons := []string{}
+	for _, i := range m.Icons {
+		icons = append(icons, i.Src+" "+i.Purpose)
+	}
+	if strings.Join(icons, ", ") != expected {
+		t.Error(fe(expected, strings.Join(icons, ", ")))
+	}
+}
@@ -0,0 +1,139 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/ingmardrewing/gomic/config"
+	"github.com/nfnt/resize"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	CardWidth     = 1200
+	CardHeight    = 630
+	cardArtWidth  = 600
+	cardMargin    = 40
+	cardLogoWidth = 200
+)
+
+type CardPage interface {
+	GetImageFilename() string
+	GetTitle() string
+	GetAct() string
+}
+
+func CardFilename(p CardPage) string {
+	return "card_" + p.GetImageFilename()
+}
+
+func CardPath(p CardPage) string {
+	return config.PngDir() + CardFilename(p)
+}
+
+func CardExists(p CardPage) bool {
+	return fileExists(CardPath(p))
+}
+
+// CreateCard composes the 1200x630 social preview card of a page
+// from its art, title, act and the site logo, unless it exists already.
+func CreateCard(p CardPage) error {
+	if CardExists(p) {
+		return nil
+	}
+	art, err := readPng(config.PngDir() + p.GetImageFilename())
+	if err != nil {
+		return err
+	}
+	var logo image.Image
+	if len(config.Logo()) > 0 {
+		logo, err = readPng(config.Logo())
+		if err != nil {
+			return err
+		}
+	}
+	return writePng(CardPath(p), composeCard(art, logo, p.GetTitle(), p.GetAct()))
+}
+
+func composeCard(art image.Image, logo image.Image, title string, act string) image.Image {
+	card := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	draw.Draw(card, card.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	scaled := resize.Resize(cardArtWidth, 0, art, resize.Lanczos3)
+	draw.Draw(card, image.Rect(0, 0, cardArtWidth, CardHeight), scaled, scaled.Bounds().Min, draw.Src)
+
+	left := cardArtWidth + cardMargin
+	textWidth := CardWidth - left - cardMargin
+
+	actFace := newFace(goregular.TTF, 28)
+	drawText(card, actFace, color.Gray{0xaa}, left, cardMargin+28, strings.ToUpper(act))
+
+	titleFace := newFace(gobold.TTF, 56)
+	y := cardMargin + 28 + 80
+	for _, line := range wrapText(titleFace, title, textWidth) {
+		drawText(card, titleFace, color.White, left, y, line)
+		y += 66
+	}
+
+	if logo != nil {
+		l := resize.Resize(cardLogoWidth, 0, logo, resize.Lanczos3)
+		b := l.Bounds()
+		x0 := CardWidth - cardMargin - b.Dx()
+		y0 := CardHeight - cardMargin - b.Dy()
+		draw.Draw(card, image.Rect(x0, y0, x0+b.Dx(), y0+b.Dy()), l, b.Min, draw.Over)
+	}
+	return card
+}
+
+func newFace(ttf []byte, size float64) font.Face {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+func drawText(dst draw.Image, face font.Face, c color.Color, x int, y int, txt string) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(txt)
+}
+
+func wrapText(face font.Face, txt string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, w := range strings.Fields(txt) {
+		candidate := w
+		if len(line) > 0 {
+			candidate = line + " " + w
+		}
+		if len(line) > 0 && font.MeasureString(face, candidate).Ceil() > width {
+			lines = append(lines, line)
+			line = w
+		} else {
+			line = candidate
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,66 @@
+package img
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobold"
+)
+
+func TestComposeCard(t *testing.T) {
+	art := image.NewRGBA(image.Rect(0, 0, 800, 1334))
+	logo := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	card := composeCard(art, logo, "#85 The Test", "Act III")
+
+	b := card.Bounds()
+	if b.Dx() != CardWidth || b.Dy() != CardHeight {
+		t.Errorf("Expected card of %dx%d, but got %dx%d", CardWidth, CardHeight, b.Dx(), b.Dy())
+	}
+}
+
+func TestComposeCardWithoutLogo(t *testing.T) {
+	art := image.NewRGBA(image.Rect(0, 0, 800, 1334))
+	card := composeCard(art, nil, "#85 The Test", "Act III")
+
+	r, g, b, _ := card.At(CardWidth-1, CardHeight-1).RGBA()
+	if r != 0 || g != 0 || b != 0 {
+		t.Errorf("Expected black background, but got %v", card.At(CardWidth-1, CardHeight-1))
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	face := newFace(gobold.TTF, 56)
+	lines := wrapText(face, "#10 Welcome to the machine", 520)
+	if len(lines) < 2 {
+		t.Errorf("Expected title to be wrapped, but got %v", lines)
+	}
+
+	lines = wrapText(face, "#3", 520)
+	if len(lines) != 1 || lines[0] != "#3" {
+		t.Errorf("Expected single line #3, but got %v", lines)
+	}
+}
+
+func TestCardFilename(t *testing.T) {
+	expected := "card_DevAbode_0085.png"
+	actual := CardFilename(pageMock{})
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
+
+// ******  mocking
+
+type pageMock struct{}
+
+func (p pageMock) GetImageFilename() string {
+	return "DevAbode_0085.png"
+}
+
+func (p pageMock) GetTitle() string {
+	return "#85 The Test"
+}
+
+func (p pageMock) GetAct() string {
+	return "Act III"
+}
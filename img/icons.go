@@ -0,0 +1,163 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+// Icon is a square icon rendered from the source image. Icons with a
+// rel are linked from the page head, those with a purpose are listed
+// in the web app manifest.
+type Icon struct {
+	Rel     string
+	Prefix  string
+	Size    int
+	Purpose string
+}
+
+// Icons are written to /icons/ in the order they are linked.
+var Icons = []Icon{
+	{"icon", "android-icon", 192, "any"},
+	{"icon", "favicon", 32, ""},
+	{"icon", "favicon", 96, ""},
+	{"icon", "favicon", 16, ""},
+	{"apple-touch-icon", "apple-icon", 57, ""},
+	{"apple-touch-icon", "apple-icon", 60, ""},
+	{"apple-touch-icon", "apple-icon", 72, ""},
+	{"apple-touch-icon", "apple-icon", 76, ""},
+	{"apple-touch-icon", "apple-icon", 114, ""},
+	{"apple-touch-icon", "apple-icon", 120, ""},
+	{"apple-touch-icon", "apple-icon", 144, ""},
+	{"apple-touch-icon", "apple-icon", 152, ""},
+	{"apple-touch-icon", "apple-icon", 180, ""},
+	{"", "android-icon", 512, "any"},
+	{"", "maskable-icon", 512, "maskable"},
+}
+
+// FaviconSizes are the sizes bundled in /favicon.ico.
+var FaviconSizes = []int{16, 32, 48}
+
+// maskableScale keeps the icon inside the safe zone of maskable
+// icons, a circle of 80% of the icon size.
+const maskableScale = 0.8
+
+func (i Icon) Filename() string {
+	return fmt.Sprintf("%s-%dx%d.png", i.Prefix, i.Size, i.Size)
+}
+
+func (i Icon) Sizes() string {
+	return fmt.Sprintf("%dx%d", i.Size, i.Size)
+}
+
+// CreateIcons renders the icons and favicon.ico from the square png
+// at src into dir and root. Icons newer than the source are kept.
+func CreateIcons(src string, dir string, root string, background string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	source, err := readPng(src)
+	if err != nil {
+		return err
+	}
+	if b := source.Bounds(); b.Dx() != b.Dy() {
+		return fmt.Errorf("icon %s is %dx%d, but must be square", src, b.Dx(), b.Dy())
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, i := range Icons {
+		path := filepath.Join(dir, i.Filename())
+		if isNewer(path, info) {
+			continue
+		}
+		var icon image.Image
+		if i.Purpose == "maskable" {
+			icon = maskableIcon(source, i.Size, parseHexColor(background))
+		} else {
+			icon = resize.Resize(uint(i.Size), uint(i.Size), source, resize.Lanczos3)
+		}
+		if err := writePng(path, icon); err != nil {
+			return err
+		}
+	}
+	ico := filepath.Join(root, "favicon.ico")
+	if isNewer(ico, info) {
+		return nil
+	}
+	data, err := encodeIco(source, FaviconSizes)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ico, data, 0644)
+}
+
+func isNewer(path string, than os.FileInfo) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.ModTime().After(than.ModTime())
+}
+
+// maskableIcon centers the scaled source in the safe zone on the
+// background, which platforms may crop to any shape.
+func maskableIcon(source image.Image, size int, bg color.Color) image.Image {
+	icon := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(icon, icon.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	inner := int(float64(size) * maskableScale)
+	scaled := resize.Resize(uint(inner), uint(inner), source, resize.Lanczos3)
+	offset := (size - inner) / 2
+	draw.Draw(icon, image.Rect(offset, offset, offset+inner, offset+inner), scaled, scaled.Bounds().Min, draw.Over)
+	return icon
+}
+
+// parseHexColor reads colors like #FF8800, white is used for any
+// other value.
+func parseHexColor(s string) color.Color {
+	s = strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return color.White
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
+
+// encodeIco bundles png encoded versions of the source in the given
+// sizes into an ico file, as supported since Windows Vista and by all
+// current browsers.
+func encodeIco(source image.Image, sizes []int) ([]byte, error) {
+	images := [][]byte{}
+	for _, s := range sizes {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, resize.Resize(uint(s), uint(s), source, resize.Lanczos3)); err != nil {
+			return nil, err
+		}
+		images = append(images, buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, []uint16{0, 1, uint16(len(sizes))})
+	offset := 6 + 16*len(sizes)
+	for i, s := range sizes {
+		// a width and height of 0 stands for 256 pixels
+		dim := uint8(s % 256)
+		out.Write([]byte{dim, dim, 0, 0})
+		binary.Write(&out, binary.LittleEndian, []uint16{1, 32})
+		binary.Write(&out, binary.LittleEndian, []uint32{uint32(len(images[i])), uint32(offset)})
+		offset += len(images[i])
+	}
+	for _, data := range images {
+		out.Write(data)
+	}
+	return out.Bytes(), nil
+}
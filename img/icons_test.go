@@ -0,0 +1,87 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSource(t *testing.T, dir string, w int, h int) string {
+	i := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(i, i.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	path := filepath.Join(dir, "source.png")
+	if err := writePng(path, i); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCreateIcons(t *testing.T) {
+	dir, err := ioutil.TempDir("", "icons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := writeSource(t, dir, 600, 600)
+
+	if err := CreateIcons(src, filepath.Join(dir, "icons"), dir, "#FF8800"); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range Icons {
+		icon, err := readPng(filepath.Join(dir, "icons", i.Filename()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := icon.Bounds(); b.Dx() != i.Size || b.Dy() != i.Size {
+			t.Errorf("Expected %s to be %s, but got %v", i.Filename(), i.Sizes(), b)
+		}
+	}
+
+	maskable, _ := readPng(filepath.Join(dir, "icons", "maskable-icon-512x512.png"))
+	expected := color.RGBA{0xff, 0x88, 0x00, 0xff}
+	if actual := color.RGBAModel.Convert(maskable.At(0, 0)); actual != expected {
+		t.Errorf("Expected the background %v in the corner, but got %v", expected, actual)
+	}
+
+	ico, err := ioutil.ReadFile(filepath.Join(dir, "favicon.ico"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make([]uint16, 3)
+	binary.Read(bytes.NewReader(ico[:6]), binary.LittleEndian, header)
+	if header[0] != 0 || header[1] != 1 || int(header[2]) != len(FaviconSizes) {
+		t.Errorf("Expected an ico header with %d images, but got %v", len(FaviconSizes), header)
+	}
+	if ico[6] != 16 || ico[22] != 32 || string(ico[6+16*len(FaviconSizes)+1:][:3]) != "PNG" {
+		t.Errorf("Expected png entries of 16 and 32 pixels, but got %v", ico[:40])
+	}
+}
+
+func TestCreateIconsFromNonSquareImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "icons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := writeSource(t, dir, 600, 400)
+
+	if err := CreateIcons(src, filepath.Join(dir, "icons"), dir, ""); err == nil {
+		t.Error("Expected an error for a non-square source")
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	expected := color.RGBA{0x12, 0x34, 0x56, 0xff}
+	if actual := parseHexColor("#123456"); actual != expected {
+		t.Errorf("Expected %v, but got %v", expected, actual)
+	}
+	if actual := parseHexColor("white"); actual != color.White {
+		t.Errorf("Expected white, but got %v", actual)
+	}
+}
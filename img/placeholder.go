@@ -0,0 +1,56 @@
+package img
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/nfnt/resize"
+)
+
+const placeholderWidth = 8
+
+// Placeholder computes the average colour of the png at path and a
+// tiny version of it as data uri. Scaled up by the browser, the tiny
+// image shows as a blurred preview until the real image has loaded.
+func Placeholder(path string) (string, string, error) {
+	i, err := readPng(path)
+	if err != nil {
+		return "", "", err
+	}
+	uri, err := placeholderDataUri(i)
+	if err != nil {
+		return "", "", err
+	}
+	return averageColor(i), uri, nil
+}
+
+func placeholderDataUri(i image.Image) (string, error) {
+	tiny := resize.Resize(placeholderWidth, 0, i, resize.Bilinear)
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, tiny); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func averageColor(i image.Image) string {
+	b := i.Bounds()
+	var r, g, bl, n uint64
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cr, cg, cb, _ := i.At(x, y).RGBA()
+			r += uint64(cr >> 8)
+			g += uint64(cg >> 8)
+			bl += uint64(cb >> 8)
+			n++
+		}
+	}
+	if n == 0 {
+		return "#ffffff"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r/n, g/n, bl/n)
+}
@@ -0,0 +1,37 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+)
+
+func TestAverageColor(t *testing.T) {
+	i := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	i.Set(0, 0, color.Black)
+	i.Set(1, 0, color.RGBA{0xff, 0x80, 0x00, 0xff})
+
+	expected := "#7f4000"
+	actual := averageColor(i)
+	if actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
+
+func TestPlaceholderDataUri(t *testing.T) {
+	i := image.NewRGBA(image.Rect(0, 0, 150, 250))
+	draw.Draw(i, i.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	uri, err := placeholderDataUri(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(uri, "data:image/png;base64,") {
+		t.Errorf("Expected png data uri, but got %s", uri)
+	}
+	if len(uri) > 400 {
+		t.Errorf("Expected a tiny placeholder, but got %d bytes", len(uri))
+	}
+}
@@ -0,0 +1,74 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+type textChunk struct {
+	keyword string
+	text    string
+}
+
+// addTextChunks inserts tEXt chunks (or iTXt chunks for text that
+// can't be represented in Latin-1) right behind the IHDR chunk of the
+// encoded png.
+func addTextChunks(data []byte, chunks []textChunk) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("not a png")
+	}
+	ihdrEnd := len(pngSignature) + 4 + 4 + 13 + 4
+	if len(data) < ihdrEnd || string(data[12:16]) != "IHDR" {
+		return nil, errors.New("png doesn't start with IHDR")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data[:ihdrEnd])
+	for _, c := range chunks {
+		if len(c.text) == 0 {
+			continue
+		}
+		if latin1, ok := toLatin1(c.text); ok {
+			writeChunk(&buf, "tEXt", append(append([]byte(c.keyword), 0), latin1...))
+		} else {
+			writeChunk(&buf, "iTXt", iTxtData(c))
+		}
+	}
+	buf.Write(data[ihdrEnd:])
+	return buf.Bytes(), nil
+}
+
+func iTxtData(c textChunk) []byte {
+	var d bytes.Buffer
+	d.WriteString(c.keyword)
+	// null separator, compression flag, compression method,
+	// empty language tag and empty translated keyword
+	d.Write([]byte{0, 0, 0, 0, 0})
+	d.WriteString(c.text)
+	return d.Bytes()
+}
+
+func writeChunk(buf *bytes.Buffer, name string, data []byte) {
+	binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(name))
+	crc.Write(data)
+	buf.WriteString(name)
+	buf.Write(data)
+	binary.Write(buf, binary.BigEndian, crc.Sum32())
+}
+
+func toLatin1(s string) ([]byte, bool) {
+	b := []byte{}
+	for _, r := range s {
+		if r > 0xff {
+			return nil, false
+		}
+		b = append(b, byte(r))
+	}
+	return b, true
+}
@@ -0,0 +1,91 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestAddTextChunks(t *testing.T) {
+	data := encodedPng(t)
+	chunks := []textChunk{
+		{"Author", "Ingmar Drewing"},
+		{"Copyright", "© 2013-2017 Ingmar Drewing"},
+		{"Title", "#85 Тест"},
+		{"Comment", ""},
+	}
+	tagged, err := addTextChunks(data, chunks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := png.Decode(bytes.NewReader(tagged)); err != nil {
+		t.Errorf("Expected tagged png to decode, but got %v", err)
+	}
+
+	texts := readTextChunks(tagged)
+	for _, c := range chunks[:3] {
+		if texts[c.keyword] != c.text {
+			t.Errorf("Expected %s to be %q, but got %q", c.keyword, c.text, texts[c.keyword])
+		}
+	}
+	if _, ok := texts["Comment"]; ok {
+		t.Error("Expected empty Comment to be left out")
+	}
+}
+
+func TestAddTextChunksToNonPng(t *testing.T) {
+	_, err := addTextChunks([]byte("GIF89a"), []textChunk{{"Author", "me"}})
+	if err == nil {
+		t.Error("Expected error for non png data")
+	}
+}
+
+func encodedPng(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// readTextChunks returns the tEXt and uncompressed iTXt entries of an
+// encoded png keyed by keyword.
+func readTextChunks(data []byte) map[string]string {
+	texts := map[string]string{}
+	pos := len(pngSignature)
+	for pos+8 <= len(data) {
+		l := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		name := string(data[pos+4 : pos+8])
+		if pos+12+l > len(data) {
+			break
+		}
+		d := data[pos+8 : pos+8+l]
+		switch name {
+		case "tEXt":
+			if i := bytes.IndexByte(d, 0); i > 0 {
+				texts[string(d[:i])] = latin1ToString(d[i+1:])
+			}
+		case "iTXt":
+			parts := bytes.SplitN(d, []byte{0}, 2)
+			if len(parts) == 2 && len(parts[1]) > 2 && parts[1][0] == 0 {
+				rest := bytes.SplitN(parts[1][2:], []byte{0}, 3)
+				if len(rest) == 3 {
+					texts[string(parts[0])] = string(rest[2])
+				}
+			}
+		}
+		pos += 12 + l
+	}
+	return texts
+}
+
+func latin1ToString(b []byte) string {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
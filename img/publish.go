@@ -0,0 +1,106 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+type PublishPage interface {
+	GetImageFilename() string
+	GetTitle() string
+	GetProdUrl() string
+}
+
+// PrepareUpload writes the page image, its thumbnail and its card to
+// config.UploadDir, watermarked and tagged with copyright metadata as
+// configured. The originals in config.PngDir stay as they are.
+func PrepareUpload(p PublishPage) error {
+	if !config.ProcessesImages() {
+		return nil
+	}
+	if err := os.MkdirAll(config.UploadDir(), 0755); err != nil {
+		return err
+	}
+
+	wm, err := newWatermarkFromConfig()
+	if err != nil {
+		return err
+	}
+	meta := metadataFor(p)
+
+	files := []struct {
+		name string
+		wm   *watermark
+	}{
+		{p.GetImageFilename(), wm},
+		{"thumb_" + p.GetImageFilename(), nil},
+		{"card_" + p.GetImageFilename(), nil},
+	}
+	for _, f := range files {
+		from := config.PngDir() + f.name
+		if !fileExists(from) {
+			log.Printf("no %s to prepare for upload\n", from)
+			continue
+		}
+		err := processPng(from, config.UploadDir()+f.name, f.wm, meta)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newWatermarkFromConfig() (*watermark, error) {
+	if !config.HasWatermark() {
+		return nil, nil
+	}
+	wm := &watermark{
+		text:     config.WatermarkText(),
+		position: config.WatermarkPosition(),
+		opacity:  config.WatermarkOpacity(),
+	}
+	if len(config.WatermarkLogo()) > 0 {
+		logo, err := readPng(config.WatermarkLogo())
+		if err != nil {
+			return nil, err
+		}
+		wm.logo = logo
+	}
+	return wm, nil
+}
+
+func metadataFor(p PublishPage) []textChunk {
+	return []textChunk{
+		{"Title", p.GetTitle()},
+		{"Author", config.Author()},
+		{"Copyright", config.Copyright()},
+		{"URL", p.GetProdUrl()},
+	}
+}
+
+func processPng(from string, to string, wm *watermark, meta []textChunk) error {
+	src, err := readPng(from)
+	if err != nil {
+		return err
+	}
+	var out image.Image = src
+	if wm != nil {
+		out = wm.apply(src)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return err
+	}
+	data, err := addTextChunks(buf.Bytes(), meta)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(to, data, 0644)
+}
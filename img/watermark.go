@@ -0,0 +1,100 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/nfnt/resize"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+)
+
+type watermark struct {
+	text     string
+	logo     image.Image
+	position string
+	opacity  float64
+}
+
+// apply returns a copy of src with the watermark drawn onto it.
+func (w *watermark) apply(src image.Image) image.Image {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+
+	mark := w.render(b.Dx())
+	if mark == nil {
+		return dst
+	}
+	margin := b.Dx() / 40
+	r := placeMark(dst.Bounds(), mark.Bounds(), w.position, margin)
+	mask := image.NewUniform(color.Alpha{uint8(w.opacity * 0xff)})
+	draw.DrawMask(dst, r, mark, mark.Bounds().Min, mask, image.Point{}, draw.Over)
+	return dst
+}
+
+// render draws the logo and the text of the watermark beneath each
+// other onto a transparent layer sized relative to the image width.
+func (w *watermark) render(width int) image.Image {
+	var logo image.Image
+	if w.logo != nil {
+		logo = resize.Resize(uint(width/5), 0, w.logo, resize.Lanczos3)
+	}
+
+	var face font.Face
+	textW, textH := 0, 0
+	if len(w.text) > 0 {
+		size := float64(width) / 30
+		face = newFace(gobold.TTF, size)
+		textW = font.MeasureString(face, w.text).Ceil() + 2
+		textH = int(size*1.3) + 2
+	}
+
+	markW, markH := textW, textH
+	if logo != nil {
+		if logo.Bounds().Dx() > markW {
+			markW = logo.Bounds().Dx()
+		}
+		markH += logo.Bounds().Dy()
+	}
+	if markW == 0 || markH == 0 {
+		return nil
+	}
+
+	mark := image.NewRGBA(image.Rect(0, 0, markW, markH))
+	y := 0
+	if logo != nil {
+		lb := logo.Bounds()
+		x := markW - lb.Dx()
+		draw.Draw(mark, image.Rect(x, 0, x+lb.Dx(), lb.Dy()), logo, lb.Min, draw.Over)
+		y = lb.Dy()
+	}
+	if face != nil {
+		baseline := y + face.Metrics().Ascent.Ceil()
+		x := markW - textW
+		// a light outline keeps the text readable on dark and bright art
+		drawText(mark, face, color.White, x, baseline+2, w.text)
+		drawText(mark, face, color.White, x+2, baseline, w.text)
+		drawText(mark, face, color.Black, x+1, baseline+1, w.text)
+	}
+	return mark
+}
+
+func placeMark(img image.Rectangle, mark image.Rectangle, position string, margin int) image.Rectangle {
+	w, h := mark.Dx(), mark.Dy()
+	var x, y int
+	switch position {
+	case "top-left":
+		x, y = margin, margin
+	case "top-right":
+		x, y = img.Dx()-w-margin, margin
+	case "bottom-left":
+		x, y = margin, img.Dy()-h-margin
+	case "center":
+		x, y = (img.Dx()-w)/2, (img.Dy()-h)/2
+	default:
+		x, y = img.Dx()-w-margin, img.Dy()-h-margin
+	}
+	return image.Rect(x, y, x+w, y+h)
+}
@@ -0,0 +1,61 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestPlaceMark(t *testing.T) {
+	page := image.Rect(0, 0, 800, 1334)
+	mark := image.Rect(0, 0, 100, 50)
+
+	tests := []struct {
+		position string
+		expected image.Rectangle
+	}{
+		{"top-left", image.Rect(20, 20, 120, 70)},
+		{"top-right", image.Rect(680, 20, 780, 70)},
+		{"bottom-left", image.Rect(20, 1264, 120, 1314)},
+		{"bottom-right", image.Rect(680, 1264, 780, 1314)},
+		{"center", image.Rect(350, 642, 450, 692)},
+		{"", image.Rect(680, 1264, 780, 1314)},
+	}
+	for _, tt := range tests {
+		actual := placeMark(page, mark, tt.position, 20)
+		if actual != tt.expected {
+			t.Errorf("Expected %s to be placed at %v, but got %v", tt.position, tt.expected, actual)
+		}
+	}
+}
+
+func TestApplyWatermarkLogo(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 400))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	logo := image.NewRGBA(image.Rect(0, 0, 80, 80))
+	draw.Draw(logo, logo.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	wm := &watermark{logo: logo, position: "top-left", opacity: 0.5}
+	out := wm.apply(src)
+
+	r, _, _, _ := out.At(20, 20).RGBA()
+	if r == 0 || r == 0xffff {
+		t.Errorf("Expected half transparent logo, but got red value %d", r)
+	}
+	r, _, _, _ = out.At(399, 399).RGBA()
+	if r != 0xffff {
+		t.Errorf("Expected untouched pixel, but got red value %d", r)
+	}
+	r, _, _, _ = src.At(20, 20).RGBA()
+	if r != 0xffff {
+		t.Error("Expected source image to stay untouched")
+	}
+}
+
+func TestRenderEmptyWatermark(t *testing.T) {
+	wm := &watermark{}
+	if wm.render(800) != nil {
+		t.Error("Expected no mark for empty watermark")
+	}
+}
@@ -3,25 +3,54 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
 	"regexp"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/ingmardrewing/gomic/aws"
+	"github.com/ingmardrewing/gomic/beacon"
 	"github.com/ingmardrewing/gomic/comic"
 	"github.com/ingmardrewing/gomic/config"
 	"github.com/ingmardrewing/gomic/db"
+	"github.com/ingmardrewing/gomic/export"
 	"github.com/ingmardrewing/gomic/fs"
+	"github.com/ingmardrewing/gomic/img"
+	"github.com/ingmardrewing/gomic/newsletter"
+	"github.com/ingmardrewing/gomic/ping"
 	"github.com/ingmardrewing/gomic/socmed"
 	"github.com/ingmardrewing/gomic/strato"
 )
 
 func main() {
 	config.Read("/Users/drewing/Sites/gomic.yaml")
+
+	switch config.Command() {
+	case "":
+		build()
+	case "export":
+		exportBooks(config.CommandArgs())
+	case "export-print":
+		exportPrint(config.CommandArgs())
+	case "beacon":
+		serveBeacon(config.CommandArgs())
+	case "announce":
+		announce(config.CommandArgs())
+	case "newsletter-server":
+		serveNewsletter(config.CommandArgs())
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s\n", config.Command())
+		os.Exit(1)
+	}
+}
+
+func build() {
 	db.Init()
 
 	pages := callPagesApi()
@@ -32,6 +61,7 @@ func main() {
 	if len(newPages) > 0 {
 		fmt.Println(newPages)
 	}
+	createMissingCards(pages.Pages)
 
 	comic := comic.NewComic(pages.Pages)
 	comic.ConnectPages()
@@ -44,11 +74,165 @@ func main() {
 	} else if config.IsProd() {
 		strato.UploadProd()
 		if len(newPages) > 0 {
+			notify(&comic, newPages)
 			socmed.Publish(&comic)
 		}
 	}
 }
 
+// notify tells the WebSub hub and IndexNow about the new pages once
+// they are online. Failures are only logged, as the pages are
+// published anyway.
+func notify(c *comic.Comic, newPages []*comic.Page) {
+	if hub := config.WebSubHub(); len(hub) > 0 {
+		if err := ping.WebSub(hub, fs.FeedUrls(c, newPages)); err != nil {
+			log.Println("websub:", err)
+		}
+	}
+	if key := config.IndexNowKey(); len(key) > 0 {
+		if err := ping.IndexNow(config.IndexNowEndpoint(), key, fs.IndexNowKeyUrl(), fs.ChangedUrls(c, newPages)); err != nil {
+			log.Println("indexnow:", err)
+		}
+	}
+}
+
+func loadComic() *comic.Comic {
+	c := comic.NewComic(callPagesApi().Pages)
+	c.ConnectPages()
+	return &c
+}
+
+func exportBooks(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	act := flags.String("act", "", "act to export, the whole comic if empty")
+	out := flags.String("out", config.ExportDir()+"/books", "output directory")
+	flags.Parse(args)
+
+	e := export.NewExporter(loadComic(), *out)
+	var files []string
+	var err error
+	if len(*act) > 0 {
+		files, err = e.ExportAct(*act)
+	} else {
+		files, err = e.ExportComic()
+	}
+	exitOnError(err)
+	for _, f := range files {
+		fmt.Println("written", f)
+	}
+}
+
+func exportPrint(args []string) {
+	flags := flag.NewFlagSet("export-print", flag.ExitOnError)
+	act := flags.String("act", "", "act to export, all acts if empty")
+	page := flags.Int("page", 0, "number of a single page to export")
+	out := flags.String("out", config.ExportDir()+"/print", "output directory")
+	flags.Parse(args)
+
+	pr := export.NewPrinter(loadComic(), *out)
+	var files []string
+	var err error
+	switch {
+	case *page > 0:
+		files, err = asList(pr.PrintPage(*page))
+	case len(*act) > 0:
+		files, err = asList(pr.PrintAct(*act))
+	default:
+		files, err = pr.PrintActs()
+	}
+	exitOnError(err)
+	for _, f := range files {
+		fmt.Println("written", f)
+	}
+}
+
+// serveBeacon receives the page views of the cookie-less analytics
+// beacon and appends them to a log file.
+func serveBeacon(args []string) {
+	flags := flag.NewFlagSet("beacon", flag.ExitOnError)
+	addr := flags.String("addr", ":8081", "address to listen on")
+	out := flags.String("log", "pageviews.log", "file the page views are appended to")
+	flags.Parse(args)
+
+	f, err := os.OpenFile(*out, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	exitOnError(err)
+	defer f.Close()
+
+	endpoint, err := url.Parse(config.BeaconEndpoint())
+	exitOnError(err)
+	http.Handle(endpoint.Path, beacon.NewHandler(f))
+	log.Printf("receiving page views at %s%s\n", *addr, endpoint.Path)
+	exitOnError(http.ListenAndServe(*addr, nil))
+}
+
+// announce mails the pages published since the last announcement to
+// the newsletter recipients and subscribers, or writes the mails to a
+// directory.
+func announce(args []string) {
+	flags := flag.NewFlagSet("announce", flag.ExitOnError)
+	out := flags.String("out", "", "directory to write .eml files to instead of sending")
+	subscribers := flags.Bool("subscribers", true, "mail the subscribers in the db, too")
+	flags.Parse(args)
+
+	pages := newsletter.NewPages(loadComic(), newsletter.ReadAnnounced(config.AnnouncedFile()))
+	if len(pages) == 0 {
+		fmt.Println("no new pages to announce")
+		return
+	}
+
+	var sender newsletter.Sender
+	if len(*out) > 0 {
+		sender = newsletter.NewEmlWriter(*out)
+	} else {
+		sender = newsletter.NewSmtpSender(config.SmtpServer(), config.NewsletterFrom(), config.SmtpUser(), config.SmtpPassword())
+	}
+	recipients := newsletter.Recipients(config.NewsletterTo())
+	if *subscribers {
+		db.Init()
+		subs, err := newsletter.SubscriberRecipients(db.NewSubscribers())
+		exitOnError(err)
+		recipients = append(recipients, subs...)
+	}
+	a := newsletter.NewAnnouncement(pages)
+	if len(*out) > 0 {
+		_, err := a.Announce(sender, recipients)
+		exitOnError(err)
+	} else {
+		exitOnError(a.AnnounceAndRecord(sender, recipients, config.AnnouncedFile()))
+	}
+	fmt.Printf("announced %d pages\n", len(pages))
+}
+
+// serveNewsletter runs the subscription service the sign up form of
+// the site puts addresses to.
+func serveNewsletter(args []string) {
+	flags := flag.NewFlagSet("newsletter-server", flag.ExitOnError)
+	addr := flags.String("addr", ":16443", "address to listen on")
+	cert := flags.String("cert", "", "tls certificate file, plain http if empty")
+	key := flags.String("key", "", "tls key file")
+	flags.Parse(args)
+
+	db.Init()
+	sender := newsletter.NewSmtpSender(config.SmtpServer(), config.NewsletterFrom(), config.SmtpUser(), config.SmtpPassword())
+	server := newsletter.NewServer(db.NewSubscribers(), sender, config.NewsletterOrigin())
+	log.Printf("receiving newsletter sign ups at %s%s\n", *addr, newsletter.ApiPath)
+	if len(*cert) > 0 {
+		exitOnError(http.ListenAndServeTLS(*addr, *cert, *key, server.Handler()))
+	}
+	exitOnError(http.ListenAndServe(*addr, server.Handler()))
+}
+
+func asList(file string, err error) ([]string, error) {
+	return []string{file}, err
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
 func callPagesApi() *comic.Pages {
 	url := "https://drewing.eu:8443/0.1/gomic/page/"
 	log.Printf("callPagesApi with %s\n", url)
@@ -81,6 +265,12 @@ func checkForNewPages(filenames []string, knownPages []*comic.Page) []*comic.Pag
 		if isNewFile(f, knownPages) {
 			comic.CreateThumbnail(f)
 			p := comic.NewPageFromFilename(f)
+			if err := img.CreateCard(p); err != nil {
+				panic(err)
+			}
+			if err := img.PrepareUpload(p); err != nil {
+				panic(err)
+			}
 			aws.UploadPage(p)
 			db.InsertPage(p)
 			newPages = append(newPages, p)
@@ -90,6 +280,23 @@ func checkForNewPages(filenames []string, knownPages []*comic.Page) []*comic.Pag
 	return newPages
 }
 
+func createMissingCards(pages []*comic.Page) {
+	for _, p := range pages {
+		if img.CardExists(p) {
+			continue
+		}
+		if err := img.CreateCard(p); err != nil {
+			log.Printf("couldn't create card for %s: %v\n", p.GetImageFilename(), err)
+			continue
+		}
+		if err := img.PrepareUpload(p); err != nil {
+			log.Printf("couldn't prepare upload of %s: %v\n", p.GetImageFilename(), err)
+			continue
+		}
+		aws.UploadCard(p)
+	}
+}
+
 func isNewFile(filename string, knownPages []*comic.Page) bool {
 	if !isRelevant(filename) {
 		return false
@@ -109,7 +316,7 @@ func isRelevant(filename string) bool {
 	if filename == irr {
 		return false
 	}
-	thumb := regexp.MustCompile(`^thumb_`)
+	thumb := regexp.MustCompile(`^(thumb|card)_`)
 	if thumb.MatchString(filename) {
 		return false
 	}
@@ -32,6 +32,11 @@ func TestIsRelevant(t *testing.T) {
 		t.Error("Expected result to be false, but it is true")
 	}
 
+	result = isRelevant("card_DevAbode_0085.png")
+	if result {
+		t.Error("Expected result to be false, but it is true")
+	}
+
 	result = isRelevant("DevAbode_0085.png")
 	if !result {
 		t.Error("Expected result to be true, but it is false")
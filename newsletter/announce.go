@@ -0,0 +1,231 @@
+package newsletter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/*.txt"))
+)
+
+// Announcement is the mail telling the subscribers about new pages.
+type Announcement struct {
+	pages []*comic.Page
+}
+
+func NewAnnouncement(pages []*comic.Page) *Announcement {
+	return &Announcement{pages}
+}
+
+type announcedPage struct {
+	Title       string
+	Description string
+	Act         string
+	Url         string
+	Thumbnail   string
+}
+
+// mailData is what the templates get to render, the unsubscribe url
+// differs per recipient.
+type mailData struct {
+	Site        string
+	SiteUrl     string
+	Subject     string
+	Pages       []announcedPage
+	Unsubscribe string
+}
+
+func (a *Announcement) Subject() string {
+	if len(a.pages) == 1 {
+		return "New at " + config.SiteTitle() + ": " + a.pages[0].GetTitle()
+	}
+	return fmt.Sprintf("%d new pages at %s", len(a.pages), config.SiteTitle())
+}
+
+func (a *Announcement) data(unsubscribe string) mailData {
+	pages := []announcedPage{}
+	for _, p := range a.pages {
+		pages = append(pages, announcedPage{p.GetTitle(), p.GetDescription(), p.GetAct(), p.GetProdUrl(), p.GetThumnailUrl()})
+	}
+	return mailData{config.SiteTitle(), config.SiteUrl(), a.Subject(), pages, unsubscribe}
+}
+
+// Message renders the mail to a recipient as multipart/alternative
+// with a plain text and an html part.
+func (a *Announcement) Message(to string, unsubscribe string, date time.Time) ([]byte, error) {
+	data := a.data(unsubscribe)
+	var txt, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&txt, "text", data); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	mp := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{{"text/plain", txt.Bytes()}, {"text/html", html.Bytes()}} {
+		w, err := mp.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		qp.Write(part.content)
+		qp.Close()
+	}
+	mp.Close()
+
+	headers := mailHeaders(to, a.Subject(), messageId(to, a.pages, date), date)
+	headers = append(headers, [2]string{"Content-Type", `multipart/alternative; boundary="` + mp.Boundary() + `"`})
+	if len(unsubscribe) > 0 {
+		headers = append(headers, [2]string{"List-Unsubscribe", "<" + unsubscribe + ">"}, [2]string{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"})
+	}
+	return message(headers, body.Bytes()), nil
+}
+
+func mailHeaders(to string, subject string, id string, date time.Time) [][2]string {
+	return [][2]string{
+		{"From", config.NewsletterFrom()},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", id},
+		{"MIME-Version", "1.0"},
+	}
+}
+
+func message(headers [][2]string, body []byte) []byte {
+	var msg bytes.Buffer
+	for _, h := range headers {
+		msg.WriteString(h[0] + ": " + h[1] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body)
+	return msg.Bytes()
+}
+
+func messageId(to string, pages []*comic.Page, date time.Time) string {
+	h := sha256.New()
+	fmt.Fprintln(h, to, date.UnixNano())
+	for _, p := range pages {
+		fmt.Fprintln(h, p.GetPath())
+	}
+	return idFromHash(h.Sum(nil))
+}
+
+func idFromHash(sum []byte) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(config.SiteUrl(), "https://"), "http://")
+	return fmt.Sprintf("<%x@%s>", sum[:12], host)
+}
+
+// NewPages are the pages published after the last announced one. If
+// none was announced yet, only the latest page is new, so the first
+// announcement doesn't mail the whole archive.
+func NewPages(c *comic.Comic, lastAnnounced string) []*comic.Page {
+	pages := c.GetPages()
+	if len(pages) == 0 {
+		return pages
+	}
+	for i, p := range pages {
+		if p.FSPath() == lastAnnounced {
+			return pages[i+1:]
+		}
+	}
+	return pages[len(pages)-1:]
+}
+
+// ReadAnnounced returns the path of the last announced page kept in
+// the given file, an empty string if there is none.
+func ReadAnnounced(file string) string {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func WriteAnnounced(file string, p *comic.Page) error {
+	return ioutil.WriteFile(file, []byte(p.FSPath()+"\n"), 0644)
+}
+
+// Recipient is an address the newsletter is sent to, subscribers
+// get a link to unsubscribe.
+type Recipient struct {
+	To          string
+	Unsubscribe string
+}
+
+// Recipients turns plain addresses into recipients without an
+// unsubscribe link.
+func Recipients(addresses []string) []Recipient {
+	recipients := []Recipient{}
+	for _, a := range addresses {
+		recipients = append(recipients, Recipient{a, ""})
+	}
+	return recipients
+}
+
+// Announce sends the announcement to each recipient and returns how
+// many got it. A failing recipient doesn't keep the others from
+// getting it, the failures are returned together.
+func (a *Announcement) Announce(s Sender, recipients []Recipient) (int, error) {
+	now := time.Now()
+	failed := []string{}
+	for _, r := range recipients {
+		msg, err := a.Message(r.To, r.Unsubscribe, now)
+		if err == nil {
+			err = s.Send(r.To, msg)
+		}
+		if err != nil {
+			failed = append(failed, r.To+": "+err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return len(recipients) - len(failed), fmt.Errorf("announcing to %d of %d recipients failed:\n%s", len(failed), len(recipients), strings.Join(failed, "\n"))
+	}
+	return len(recipients), nil
+}
+
+// AnnounceAndRecord announces the pages and keeps the last one in the
+// announced file once any recipient got them, the others would get
+// them twice otherwise. If nobody got them, the next run retries.
+func (a *Announcement) AnnounceAndRecord(s Sender, recipients []Recipient, announced string) error {
+	sent, err := a.Announce(s, recipients)
+	if sent > 0 {
+		if werr := WriteAnnounced(announced, a.pages[len(a.pages)-1]); werr != nil {
+			return werr
+		}
+	}
+	return err
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,259 @@
+package newsletter
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ingmardrewing/gomic/comic"
+	"github.com/ingmardrewing/gomic/config"
+)
+
+func readConfig(t *testing.T) {
+	yaml := filepath.Join(t.TempDir(), "gomic.yaml")
+	ioutil.WriteFile(yaml, []byte("aws_dir: comicstrips\nnewsletter:\n  from: DevAbo.de <newsletter@devabo.de>\n"), 0644)
+	config.ReadDirect(yaml)
+}
+
+func testPages() []*comic.Page {
+	return []*comic.Page{
+		comic.NewPage("#1", "The beginning", "/2017/01/01/page-1", "https://example.com/DevAbode_0001.png", "", "Act I"),
+		comic.NewPage("#2 Über", "Robots & <friends>", "/2017/01/02/page-2", "https://example.com/DevAbode_0002.png", "", "Act I"),
+	}
+}
+
+// parts splits a rendered mail into its headers and the decoded text
+// and html parts.
+func parts(t *testing.T, msg []byte) (mail.Header, map[string]string) {
+	m, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	boundary := strings.TrimSuffix(strings.SplitN(m.Header.Get("Content-Type"), `boundary="`, 2)[1], `"`)
+	r := multipart.NewReader(m.Body, boundary)
+	found := map[string]string{}
+	for {
+		p, err := r.NextRawPart()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(quotedprintable.NewReader(p))
+		found[strings.Split(p.Header.Get("Content-Type"), ";")[0]] = string(b)
+	}
+	return m.Header, found
+}
+
+func TestAnnouncementMessage(t *testing.T) {
+	readConfig(t)
+	a := NewAnnouncement(testPages()[1:])
+	msg, err := a.Message("reader@example.com", "https://devabo.de/unsubscribe?t=abc", time.Date(2017, 1, 2, 20, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, found := parts(t, msg)
+
+	dec := new(mime.WordDecoder)
+	subject, _ := dec.DecodeHeader(header.Get("Subject"))
+	expected := "New at DevAbo.de: #2 Über"
+	if subject != expected {
+		t.Errorf("Expected %s, but got %s", expected, subject)
+	}
+	if header.Get("To") != "reader@example.com" || header.Get("List-Unsubscribe") != "<https://devabo.de/unsubscribe?t=abc>" {
+		t.Errorf("Expected recipient and unsubscribe headers, but got %v", header)
+	}
+
+	for _, e := range []string{"A new page is online at DevAbo.de:", "Robots & <friends>", "https://devabo.de/2017/01/02/page-2", "Unsubscribe: https://devabo.de/unsubscribe?t=abc"} {
+		if !strings.Contains(found["text/plain"], e) {
+			t.Errorf("Expected %s in %s", e, found["text/plain"])
+		}
+	}
+	for _, e := range []string{
+		`<a href="https://devabo.de/2017/01/02/page-2"><img src="https://s3-us-west-1.amazonaws.com/devabode-us/comicstrips/thumb_DevAbode_0002.png"`,
+		`Robots &amp; &lt;friends&gt;`,
+		`<meta name="viewport" content="width=device-width, initial-scale=1.0">`,
+	} {
+		if !strings.Contains(found["text/html"], e) {
+			t.Errorf("Expected %s in %s", e, found["text/html"])
+		}
+	}
+}
+
+func TestNewPages(t *testing.T) {
+	readConfig(t)
+	c := comic.NewComic(append(testPages(), comic.NewPage("#3", "", "/2017/01/03/page-3", "", "", "Act I")))
+
+	titles := func(pages []*comic.Page) string {
+		t := []string{}
+		for _, p := range pages {
+			t = append(t, p.GetTitle())
+		}
+		return strings.Join(t, ", ")
+	}
+	for last, expected := range map[string]string{
+		"/2017/01/01/page-1": "#2 Über, #3",
+		"/2017/01/03/page-3": "",
+		"":                   "#3",
+	} {
+		if actual := titles(NewPages(&c, last)); actual != expected {
+			t.Errorf("Expected %s after %s, but got %s", expected, last, actual)
+		}
+	}
+}
+
+// fakeSmtp is a minimal smtp server accepting a single session and
+// passing on the recipients and data it received.
+func fakeSmtp(t *testing.T) (string, chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan []string, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		got := []string{}
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM"), strings.HasPrefix(cmd, "RCPT TO"):
+				got = append(got, strings.TrimSpace(line))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				data := []string{}
+				for {
+					l, _ := r.ReadString('\n')
+					if l == ".\r\n" {
+						break
+					}
+					data = append(data, l)
+				}
+				got = append(got, strings.Join(data, ""))
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- got
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func TestAnnounceViaSmtp(t *testing.T) {
+	readConfig(t)
+	addr, received := fakeSmtp(t)
+	s := NewSmtpSender(addr, config.NewsletterFrom(), "", "")
+	if _, err := NewAnnouncement(testPages()).Announce(s, Recipients([]string{"Reader <reader@example.com>"})); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-received
+	if len(got) != 3 || got[0] != "MAIL FROM:<newsletter@devabo.de>" || got[1] != "RCPT TO:<reader@example.com>" {
+		t.Fatalf("Expected the envelope of newsletter@devabo.de, but got %v", got)
+	}
+	if !strings.Contains(got[2], "Subject: 2 new pages at DevAbo.de\r\n") {
+		t.Errorf("Expected the subject of two pages in %s", got[2])
+	}
+}
+
+func TestAnnounceToEmlFiles(t *testing.T) {
+	readConfig(t)
+	dir := t.TempDir()
+	recipients := []string{"reader@example.com", "reader@example.com"}
+	if _, err := NewAnnouncement(testPages()).Announce(NewEmlWriter(dir), Recipients(recipients)); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	expected := "reader@example.com-2.eml reader@example.com.eml"
+	names := []string{}
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	if strings.Join(names, " ") != expected {
+		t.Errorf("Expected %s, but got %s", expected, strings.Join(names, " "))
+	}
+}
+
+// failingSender fails to send to a single address.
+type failingSender struct {
+	sentMails
+	fails string
+}
+
+func (f *failingSender) Send(to string, msg []byte) error {
+	if to == f.fails {
+		return errors.New("mailbox unavailable")
+	}
+	return f.sentMails.Send(to, msg)
+}
+
+func TestAnnounceContinuesAfterFailure(t *testing.T) {
+	readConfig(t)
+	s := &failingSender{fails: "b@example.com"}
+	sent, err := NewAnnouncement(testPages()).Announce(s, Recipients([]string{"a@example.com", "b@example.com", "c@example.com"}))
+	if sent != 2 {
+		t.Errorf("Expected 2 mails sent, but got %d", sent)
+	}
+	if err == nil || !strings.Contains(err.Error(), "b@example.com: mailbox unavailable") {
+		t.Errorf("Expected the failure of b@example.com, but got %v", err)
+	}
+	if strings.Join(s.to, " ") != "a@example.com c@example.com" {
+		t.Errorf("Expected the other recipients to get the mail, but got %v", s.to)
+	}
+}
+
+func TestAnnouncedOnlyIfAnyoneGotIt(t *testing.T) {
+	readConfig(t)
+	file := filepath.Join(t.TempDir(), "announced.txt")
+	WriteAnnounced(file, testPages()[0])
+	a := NewAnnouncement(testPages()[1:])
+
+	if err := a.AnnounceAndRecord(&failingSender{fails: "a@example.com"}, Recipients([]string{"a@example.com"}), file); err == nil {
+		t.Error("Expected the failure to be returned")
+	}
+	if actual := ReadAnnounced(file); actual != "/2017/01/01/page-1" {
+		t.Errorf("Expected the announced file to be unchanged, but got %s", actual)
+	}
+
+	a.AnnounceAndRecord(&failingSender{fails: "a@example.com"}, Recipients([]string{"a@example.com", "b@example.com"}), file)
+	if actual := ReadAnnounced(file); actual != "/2017/01/02/page-2" {
+		t.Errorf("Expected the pages to be announced, but got %s", actual)
+	}
+}
+
+func TestAnnounced(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "announced.txt")
+	if ReadAnnounced(file) != "" {
+		t.Error("Expected nothing announced yet")
+	}
+	WriteAnnounced(file, testPages()[1])
+	expected := "/2017/01/02/page-2"
+	if actual := ReadAnnounced(file); actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
@@ -0,0 +1,70 @@
+package newsletter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Sender delivers a rendered mail to a recipient.
+type Sender interface {
+	Send(to string, msg []byte) error
+}
+
+type smtpSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSmtpSender sends mails through the server at addr, host:port,
+// authenticating only if a user is given.
+func NewSmtpSender(addr string, from string, user string, pass string) Sender {
+	var auth smtp.Auth
+	if len(user) > 0 {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", user, pass, host)
+	}
+	return &smtpSender{addr, from, auth}
+}
+
+func (s *smtpSender) Send(to string, msg []byte) error {
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return err
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, from.Address, []string{rcpt.Address}, msg)
+}
+
+type emlWriter struct {
+	dir string
+}
+
+// NewEmlWriter writes the mails as .eml files to dir instead of
+// sending them, e.g. to check them in a mail client first.
+func NewEmlWriter(dir string) Sender {
+	return &emlWriter{dir}
+}
+
+var unsafeFilename = regexp.MustCompile(`[^-A-Za-z0-9@._]`)
+
+func (w *emlWriter) Send(to string, msg []byte) error {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return err
+	}
+	name := unsafeFilename.ReplaceAllString(to, "_")
+	path := filepath.Join(w.dir, name+".eml")
+	for i := 2; fileExists(path); i++ {
+		path = filepath.Join(w.dir, fmt.Sprintf("%s-%d.eml", name, i))
+	}
+	return ioutil.WriteFile(path, msg, 0644)
+}
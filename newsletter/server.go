@@ -0,0 +1,289 @@
+package newsletter
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/ingmardrewing/gomic/config"
+)
+
+// ApiPath is where the subscription service answers, the sign up form
+// of the site puts the address to config.NewsletterSignUpUrl.
+const ApiPath = config.NewsletterApiPath
+
+// The texts the sign up form of the site tells apart.
+const (
+	textRegistered = "address already registered"
+	textNoAddress  = "no address given"
+	textConfirm    = "confirmation sent"
+)
+
+// confirmationInterval is the least time between two confirmation
+// mails to the same address, so the form can't be used to flood it.
+const confirmationInterval = 24 * time.Hour
+
+// Subscriber is an address signed up for the newsletter. It only
+// receives the newsletter once it confirmed the address with the
+// token, which also unsubscribes it. Notified is when the last
+// confirmation mail was sent.
+type Subscriber struct {
+	Email     string
+	Token     string
+	Confirmed bool
+	Notified  time.Time
+}
+
+// Store keeps the subscribers.
+type Store interface {
+	// Get returns the subscriber of the address, nil if there is none.
+	Get(email string) (*Subscriber, error)
+	Add(s *Subscriber) error
+	// Confirm and Remove tell whether a subscriber had the token.
+	Confirm(token string) (bool, error)
+	Remove(token string) (bool, error)
+	Confirmed() ([]*Subscriber, error)
+	// Notify records when a confirmation mail was sent to the address.
+	Notify(email string, at time.Time) error
+}
+
+// Server is the double opt-in subscription service: an address put to
+// add/ gets a mail with a link to confirm/, every newsletter mail
+// links to unsubscribe/.
+type Server struct {
+	store  Store
+	sender Sender
+	origin string
+}
+
+func NewServer(store Store, sender Sender, origin string) *Server {
+	return &Server{store, sender, origin}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ApiPath+"add/", s.cors(s.add))
+	mux.HandleFunc(ApiPath+"confirm/", s.confirm)
+	mux.HandleFunc(ApiPath+"unsubscribe/", s.unsubscribe)
+	return mux
+}
+
+// cors lets the site call the handler from the browser, answering
+// the preflight request itself.
+func (s *Server) cors(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		if r.Header.Get("Origin") == s.origin {
+			w.Header().Set("Access-Control-Allow-Origin", s.origin)
+			w.Header().Set("Access-Control-Allow-Methods", "PUT, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// signUp is the payload of the sign up form.
+type signUp struct {
+	Email string
+}
+
+type reply struct {
+	Text string
+}
+
+func (s *Server) add(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "PUT, POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var su signUp
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&su); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	email, ok := parseEmail(su.Email)
+	if !ok {
+		writeReply(w, textNoAddress)
+		return
+	}
+
+	sub, err := s.store.Get(email)
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	if sub != nil && sub.Confirmed {
+		writeReply(w, textRegistered)
+		return
+	}
+	now := time.Now()
+	if sub != nil && now.Sub(sub.Notified) < confirmationInterval {
+		writeReply(w, textConfirm)
+		return
+	}
+	if sub == nil {
+		sub = &Subscriber{email, newToken(), false, time.Time{}}
+		if err := s.store.Add(sub); err != nil {
+			serverError(w, err)
+			return
+		}
+	}
+	if err := s.sender.Send(sub.Email, confirmationMail(sub, now)); err != nil {
+		serverError(w, err)
+		return
+	}
+	if err := s.store.Notify(sub.Email, now); err != nil {
+		serverError(w, err)
+		return
+	}
+	writeReply(w, textConfirm)
+}
+
+// confirm asks to confirm with a form, too, as link scanners would
+// otherwise confirm any address signed up without its owner.
+func (s *Server) confirm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("t")
+	if r.Method != http.MethodPost {
+		writeForm(w, "Do you want to get an e-mail whenever a new page is published?", r.URL.RequestURI(), "Confirm")
+		return
+	}
+	ok := false
+	var err error
+	if len(token) > 0 {
+		ok, err = s.store.Confirm(token)
+	}
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	if !ok {
+		writePage(w, http.StatusNotFound, "This confirmation link is unknown or no longer valid.")
+		return
+	}
+	writePage(w, http.StatusOK, "Thank you! You will get an e-mail whenever a new page is published.")
+}
+
+// unsubscribe asks to confirm with a form first, so link scanners of
+// mail providers don't unsubscribe anyone. The form posts to the same
+// url, as do mail clients supporting one-click unsubscribe.
+func (s *Server) unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("t")
+	if r.Method != http.MethodPost {
+		writeForm(w, "Do you want to unsubscribe from the newsletter?", r.URL.RequestURI(), "Unsubscribe")
+		return
+	}
+	ok := false
+	var err error
+	if len(token) > 0 {
+		ok, err = s.store.Remove(token)
+	}
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	if !ok {
+		writePage(w, http.StatusNotFound, "This address isn't subscribed anymore.")
+		return
+	}
+	writePage(w, http.StatusOK, "You are unsubscribed and won't get any more e-mails.")
+}
+
+// parseEmail accepts a bare e-mail address.
+func parseEmail(s string) (string, bool) {
+	a, err := mail.ParseAddress(s)
+	if err != nil || a.Address != s {
+		return "", false
+	}
+	return a.Address, true
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func linkWithToken(action string, token string) string {
+	return config.NewsletterServer() + ApiPath + action + "/?t=" + url.QueryEscape(token)
+}
+
+// SubscriberRecipients are the confirmed subscribers, each with a
+// link to unsubscribe.
+func SubscriberRecipients(store Store) ([]Recipient, error) {
+	subs, err := store.Confirmed()
+	if err != nil {
+		return nil, err
+	}
+	recipients := []Recipient{}
+	for _, s := range subs {
+		recipients = append(recipients, Recipient{s.Email, linkWithToken("unsubscribe", s.Token)})
+	}
+	return recipients, nil
+}
+
+type confirmationData struct {
+	Site    string
+	SiteUrl string
+	Confirm string
+}
+
+func confirmationMail(s *Subscriber, date time.Time) []byte {
+	var body bytes.Buffer
+	data := confirmationData{config.SiteTitle(), config.SiteUrl(), linkWithToken("confirm", s.Token)}
+	if err := textTemplates.ExecuteTemplate(&body, "confirmation", data); err != nil {
+		panic(err)
+	}
+	sum := sha256.Sum256([]byte(s.Email + s.Token + date.String()))
+	headers := mailHeaders(s.Email, "Please confirm your subscription to "+config.SiteTitle(), idFromHash(sum[:]), date)
+	headers = append(headers, [2]string{"Content-Type", "text/plain; charset=utf-8"}, [2]string{"Content-Transfer-Encoding", "8bit"})
+	return message(headers, body.Bytes())
+}
+
+func writeReply(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply{text})
+}
+
+type pageData struct {
+	Site    string
+	SiteUrl string
+	Text    string
+	Action  string
+	Button  string
+}
+
+func writePage(w http.ResponseWriter, status int, text string) {
+	render(w, status, pageData{config.SiteTitle(), config.SiteUrl(), text, "", ""})
+}
+
+// writeForm asks to confirm an action by posting to the given url.
+func writeForm(w http.ResponseWriter, text string, action string, button string) {
+	render(w, http.StatusOK, pageData{config.SiteTitle(), config.SiteUrl(), text, action, button})
+}
+
+func render(w http.ResponseWriter, status int, data pageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := htmlTemplates.ExecuteTemplate(w, "page", data); err != nil {
+		log.Println(err)
+	}
+}
+
+func serverError(w http.ResponseWriter, err error) {
+	log.Println(err)
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
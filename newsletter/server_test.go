@@ -0,0 +1,235 @@
+package newsletter
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memStore struct {
+	subs []*Subscriber
+}
+
+func (m *memStore) Get(email string) (*Subscriber, error) {
+	for _, s := range m.subs {
+		if s.Email == email {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memStore) Add(s *Subscriber) error {
+	m.subs = append(m.subs, s)
+	return nil
+}
+
+func (m *memStore) Confirm(token string) (bool, error) {
+	for _, s := range m.subs {
+		if s.Token == token {
+			s.Confirmed = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memStore) Remove(token string) (bool, error) {
+	for i, s := range m.subs {
+		if s.Token == token {
+			m.subs = append(m.subs[:i], m.subs[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memStore) Confirmed() ([]*Subscriber, error) {
+	subs := []*Subscriber{}
+	for _, s := range m.subs {
+		if s.Confirmed {
+			subs = append(subs, s)
+		}
+	}
+	return subs, nil
+}
+
+func (m *memStore) Notify(email string, at time.Time) error {
+	for _, s := range m.subs {
+		if s.Email == email {
+			s.Notified = at
+		}
+	}
+	return nil
+}
+
+type sentMails struct {
+	to   []string
+	msgs []string
+}
+
+func (s *sentMails) Send(to string, msg []byte) error {
+	s.to = append(s.to, to)
+	s.msgs = append(s.msgs, string(msg))
+	return nil
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *memStore, *sentMails) {
+	readConfig(t)
+	store := &memStore{}
+	sent := &sentMails{}
+	ts := httptest.NewServer(NewServer(store, sent, "https://devabo.de").Handler())
+	t.Cleanup(ts.Close)
+	return ts, store, sent
+}
+
+// signUpAs puts the payload of the sign up form of the site.
+func signUpAs(t *testing.T, ts *httptest.Server, email string) string {
+	req, _ := http.NewRequest("PUT", ts.URL+ApiPath+"add/", strings.NewReader(`{"Email":"`+email+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://devabo.de")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Access-Control-Allow-Origin") != "https://devabo.de" {
+		t.Errorf("Expected the site to be allowed, but got %v", resp.Header)
+	}
+	var r reply
+	json.NewDecoder(resp.Body).Decode(&r)
+	return r.Text
+}
+
+func TestDoubleOptIn(t *testing.T) {
+	ts, store, sent := newTestServer(t)
+
+	if text := signUpAs(t, ts, "reader@example.com"); text != textConfirm {
+		t.Fatalf("Expected %s, but got %s", textConfirm, text)
+	}
+	if len(sent.to) != 1 || sent.to[0] != "reader@example.com" {
+		t.Fatalf("Expected a confirmation mail, but got %v", sent.to)
+	}
+	if subs, _ := store.Confirmed(); len(subs) != 0 {
+		t.Errorf("Expected no confirmed subscriber before the confirmation, but got %v", subs)
+	}
+
+	link := regexp.MustCompile(`https://\S+/confirm/\?t=([0-9a-f]+)`).FindStringSubmatch(sent.msgs[0])
+	if link == nil || link[1] != store.subs[0].Token {
+		t.Fatalf("Expected a confirmation link in %s", sent.msgs[0])
+	}
+	url := ts.URL + ApiPath + "confirm/?t=" + link[1]
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if subs, _ := store.Confirmed(); len(subs) != 0 {
+		t.Errorf("Expected following the link not to confirm by itself, but got %v", subs)
+	}
+	form := `<form method="post" action="` + ApiPath + "confirm/?t=" + link[1] + `"><button type="submit">Confirm</button></form>`
+	if !strings.Contains(string(body), form) {
+		t.Errorf("Expected %s in %s", form, body)
+	}
+
+	resp, err = http.Post(url, "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if subs, _ := store.Confirmed(); resp.StatusCode != http.StatusOK || len(subs) != 1 {
+		t.Errorf("Expected a confirmed subscriber, but got %d and %v", resp.StatusCode, subs)
+	}
+
+	if text := signUpAs(t, ts, "reader@example.com"); text != textRegistered {
+		t.Errorf("Expected %s, but got %s", textRegistered, text)
+	}
+}
+
+func TestConfirmationResentOncePerInterval(t *testing.T) {
+	ts, store, sent := newTestServer(t)
+	for i := 0; i < 3; i++ {
+		if text := signUpAs(t, ts, "reader@example.com"); text != textConfirm {
+			t.Errorf("Expected %s, but got %s", textConfirm, text)
+		}
+	}
+	if len(sent.to) != 1 {
+		t.Errorf("Expected a single confirmation mail, but got %v", sent.to)
+	}
+
+	store.subs[0].Notified = time.Now().Add(-confirmationInterval)
+	signUpAs(t, ts, "reader@example.com")
+	if len(sent.to) != 2 {
+		t.Errorf("Expected the confirmation to be resent after %s, but got %v", confirmationInterval, sent.to)
+	}
+}
+
+func TestSignUpWithoutAddress(t *testing.T) {
+	ts, store, sent := newTestServer(t)
+	for _, email := range []string{"", "not an address", "Reader <reader@example.com>"} {
+		if text := signUpAs(t, ts, email); text != textNoAddress {
+			t.Errorf("Expected %s for %q, but got %s", textNoAddress, email, text)
+		}
+	}
+	if len(store.subs) != 0 || len(sent.to) != 0 {
+		t.Errorf("Expected nothing stored or sent, but got %v and %v", store.subs, sent.to)
+	}
+}
+
+func TestSignUpPreflight(t *testing.T) {
+	ts, _, _ := newTestServer(t)
+	req, _ := http.NewRequest("OPTIONS", ts.URL+ApiPath+"add/", nil)
+	req.Header.Set("Origin", "https://devabo.de")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || !strings.Contains(resp.Header.Get("Access-Control-Allow-Methods"), "PUT") {
+		t.Errorf("Expected the preflight to allow PUT, but got %d %v", resp.StatusCode, resp.Header)
+	}
+
+	req.Header.Set("Origin", "https://elsewhere.example.com")
+	resp, _ = http.DefaultClient.Do(req)
+	resp.Body.Close()
+	if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected other origins not to be allowed, but got %v", resp.Header)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ts, store, _ := newTestServer(t)
+	store.Add(&Subscriber{"reader@example.com", "abc123", true, time.Time{}})
+
+	recipients, _ := SubscriberRecipients(store)
+	expected := "https://drewing.eu:16443/0.1/gomic/newsletter/unsubscribe/?t=abc123"
+	if len(recipients) != 1 || recipients[0].Unsubscribe != expected {
+		t.Fatalf("Expected %s, but got %v", expected, recipients)
+	}
+
+	url := ts.URL + ApiPath + "unsubscribe/?t=abc123"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(store.subs) != 1 {
+		t.Error("Expected following the link not to unsubscribe by itself")
+	}
+
+	resp, err = http.Post(url, "application/x-www-form-urlencoded", strings.NewReader("List-Unsubscribe=One-Click"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(store.subs) != 0 {
+		t.Errorf("Expected the subscriber to be removed, but got %d and %v", resp.StatusCode, store.subs)
+	}
+}
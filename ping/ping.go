@@ -0,0 +1,61 @@
+package ping
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// WebSub tells the hub that the topics, the feeds, changed, so it
+// fetches them and pushes the new pages to the feed readers
+// subscribed to them.
+func WebSub(hub string, topics []string) error {
+	for _, t := range topics {
+		resp, err := client.PostForm(hub, url.Values{"hub.mode": {"publish"}, "hub.url": {t}})
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("hub %s answered %s for %s", hub, resp.Status, t)
+		}
+	}
+	return nil
+}
+
+type indexNowRequest struct {
+	Host        string   `json:"host"`
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation"`
+	UrlList     []string `json:"urlList"`
+}
+
+// IndexNow submits the changed urls of the site to the search engines
+// sharing the IndexNow protocol. They verify the key at keyLocation.
+func IndexNow(endpoint string, key string, keyLocation string, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	u, err := url.Parse(urls[0])
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(indexNowRequest{u.Host, key, keyLocation, urls})
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(endpoint, "application/json; charset=utf-8", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("indexnow %s answered %s", endpoint, resp.Status)
+	}
+	return nil
+}
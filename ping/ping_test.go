@@ -0,0 +1,61 @@
+package ping
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebSub(t *testing.T) {
+	topics := []string{}
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Method != "POST" || r.Form.Get("hub.mode") != "publish" {
+			t.Errorf("Expected a publish request, but got %s %v", r.Method, r.Form)
+		}
+		topics = append(topics, r.Form.Get("hub.url"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hub.Close()
+
+	if err := WebSub(hub.URL, []string{"https://devabo.de/feed/rss.xml", "https://devabo.de/feed/atom.xml"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "https://devabo.de/feed/rss.xml https://devabo.de/feed/atom.xml"
+	if actual := strings.Join(topics, " "); actual != expected {
+		t.Errorf("Expected %s, but got %s", expected, actual)
+	}
+}
+
+func TestWebSubFailure(t *testing.T) {
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unknown topic", http.StatusBadRequest)
+	}))
+	defer hub.Close()
+
+	if err := WebSub(hub.URL, []string{"https://devabo.de/feed/rss.xml"}); err == nil {
+		t.Error("Expected an error for a rejected topic")
+	}
+}
+
+func TestIndexNow(t *testing.T) {
+	var got indexNowRequest
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json; charset=utf-8" {
+			t.Errorf("Expected json, but got %s", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer endpoint.Close()
+
+	urls := []string{"https://devabo.de/2017/01/03/page-3", "https://devabo.de/"}
+	if err := IndexNow(endpoint.URL, "0123abcd", "https://devabo.de/0123abcd.txt", urls); err != nil {
+		t.Fatal(err)
+	}
+	if got.Host != "devabo.de" || got.Key != "0123abcd" || got.KeyLocation != "https://devabo.de/0123abcd.txt" || len(got.UrlList) != 2 {
+		t.Errorf("Expected the urls of devabo.de with the key, but got %v", got)
+	}
+}
@@ -38,11 +38,12 @@ func Publish(c *comic.Comic) {
 }
 
 func getJsonData(c *comic.Comic) string {
-	lastPage := c.Get10LastComicPagesNewestFirst()[10]
+	lastPage := c.LastPage()
 	title := lastPage.GetTitle()
 	imgurl := lastPage.GetImgUrl()
+	cardurl := lastPage.GetCardUrl()
 	prodUrl := lastPage.GetProdUrl()
 	description := lastPage.GetDescription()
 	tags := "webcomic,graphicnovel,comic,comicart,comics,sciencefiction,scifi,geek,nerd,art,artist,artwork,blackandwhite,concept,conceptart,create,creative,design,digital,draw,drawing,drawings,dystopy,fantasy,humor,illustration,illustrator,image,imagination,ink,inked,inking,kunst,malen,malerei,narrative,parody,pulp,sketch,sketchbook,tusche,zeichnen,zeichnung"
-	return fmt.Sprintf(`{"Link":"%s","ImgUrl":"%s","Title":"%s","TagsCsvString":"%s","Description":"%s"}`, prodUrl, imgurl, title, tags, description)
+	return fmt.Sprintf(`{"Link":"%s","ImgUrl":"%s","CardUrl":"%s","Title":"%s","TagsCsvString":"%s","Description":"%s"}`, prodUrl, imgurl, cardurl, title, tags, description)
 }